@@ -0,0 +1,119 @@
+package gola
+
+import (
+	"context"
+	"encoding/base64"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	httpheadername "github.com/kklab-com/gone-httpheadername"
+)
+
+func (g *GoLA) RegisterAPIGatewayProxy(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	resp, err := g.serve(ctx, newAPIGatewayProxyRequest(request, nil))
+	return *resp.BuildAPIGatewayProxy(), err
+}
+
+func NewAPIGatewayProxyRequest(req events.APIGatewayProxyRequest, pathParameters map[string]string) Request {
+	return newAPIGatewayProxyRequest(req, pathParameters)
+}
+
+func newAPIGatewayProxyRequest(req events.APIGatewayProxyRequest, pathParameters map[string]string) *request {
+	r := newRequest(events.ALBTargetGroupRequest{
+		HTTPMethod:                      req.HTTPMethod,
+		Path:                            req.Path,
+		QueryStringParameters:           req.QueryStringParameters,
+		MultiValueQueryStringParameters: req.MultiValueQueryStringParameters,
+		Headers:                         req.Headers,
+		MultiValueHeaders:               req.MultiValueHeaders,
+		IsBase64Encoded:                 req.IsBase64Encoded,
+		Body:                            req.Body,
+	}, pathParameters)
+
+	// API Gateway has decoded query already
+	r.query = r.base.MultiValueQueryStringParameters
+	r.rawQuery = r.query.Encode()
+	r.event = req
+	r.stage = req.RequestContext.Stage
+	r.authorizer = req.RequestContext.Authorizer
+	if claims, ok := req.RequestContext.Authorizer["claims"].(map[string]any); ok {
+		r.claims = claims
+	}
+
+	return r
+}
+
+func (r *response) BuildAPIGatewayProxy() *events.APIGatewayProxyResponse {
+	albResp := r.Build()
+	return &events.APIGatewayProxyResponse{
+		StatusCode:        albResp.StatusCode,
+		Headers:           albResp.Headers,
+		MultiValueHeaders: albResp.MultiValueHeaders,
+		Body:              albResp.Body,
+		IsBase64Encoded:   true,
+	}
+}
+
+func (g *GoLA) RegisterAPIGatewayV2HTTP(ctx context.Context, request events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+	resp, err := g.serve(ctx, newAPIGatewayV2HTTPRequest(request, nil))
+	return *resp.BuildAPIGatewayV2HTTP(), err
+}
+
+func NewAPIGatewayV2HTTPRequest(req events.APIGatewayV2HTTPRequest, pathParameters map[string]string) Request {
+	return newAPIGatewayV2HTTPRequest(req, pathParameters)
+}
+
+func newAPIGatewayV2HTTPRequest(req events.APIGatewayV2HTTPRequest, pathParameters map[string]string) *request {
+	r := newPayloadV2Request(req.RequestContext.HTTP.Method, req.RawPath, req.RawQueryString, req.Cookies, req.Headers, req.Body, req.IsBase64Encoded, pathParameters)
+	// API Gateway has decoded query already
+	r.query = r.base.MultiValueQueryStringParameters
+	r.event = req
+	r.stage = req.RequestContext.Stage
+	if authorizer := req.RequestContext.Authorizer; authorizer != nil {
+		r.authorizer = authorizer.Lambda
+		if authorizer.JWT != nil {
+			r.claims = map[string]any{}
+			for key, value := range authorizer.JWT.Claims {
+				r.claims[key] = value
+			}
+		}
+	}
+
+	return r
+}
+
+func newPayloadV2Request(method, rawPath, rawQueryString string, cookies []string, headers map[string]string, body string, isBase64Encoded bool, pathParameters map[string]string) *request {
+	mHeaders := multiValues(headers, nil)
+	if len(cookies) > 0 {
+		if mHeaders == nil {
+			mHeaders = map[string][]string{}
+		}
+
+		mHeaders[httpheadername.Cookie] = []string{strings.Join(cookies, "; ")}
+	}
+
+	query, _ := url.ParseQuery(rawQueryString)
+	r := newRequest(events.ALBTargetGroupRequest{
+		HTTPMethod:                      method,
+		Path:                            rawPath,
+		MultiValueQueryStringParameters: query,
+		Headers:                         headers,
+		MultiValueHeaders:               mHeaders,
+		IsBase64Encoded:                 isBase64Encoded,
+		Body:                            body,
+	}, pathParameters)
+
+	r.query, r.rawQuery = query, rawQueryString
+	return r
+}
+
+func (r *response) BuildAPIGatewayV2HTTP() *events.APIGatewayV2HTTPResponse {
+	return &events.APIGatewayV2HTTPResponse{
+		StatusCode:      r.buildStatusCode(),
+		Headers:         joinHeaders(r.headers),
+		Body:            base64.StdEncoding.EncodeToString(r.body.Bytes()),
+		IsBase64Encoded: true,
+		Cookies:         r.setCookies(),
+	}
+}
@@ -0,0 +1,104 @@
+package gola
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	buf "github.com/kklab-com/goth-bytebuf"
+	"github.com/stretchr/testify/assert"
+)
+
+type APIGatewayTestHandler struct {
+	DefaultHttpHandler
+	t *testing.T
+}
+
+func (h *APIGatewayTestHandler) Get(ctx context.Context, request Request, response Response) (er error) {
+	assert.Equal(h.t, "prod", request.Stage())
+	assert.Equal(h.t, "user-1", request.Claims()["sub"])
+	assert.Equal(h.t, "principal", request.Authorizer()["principalId"])
+	assert.Equal(h.t, "123", request.PathParameter("user_id"))
+	assert.Equal(h.t, "value", request.GetHeader("X-Test"))
+	assert.Equal(h.t, []string{"1", "2"}, request.QueryValues("page"))
+	assert.IsType(h.t, events.APIGatewayProxyRequest{}, request.Event())
+	response.SetCookie(http.Cookie{Name: "session", Value: "abc"})
+	response.SetBody(buf.NewByteBufString("GET"))
+	return
+}
+
+func TestGoLA_RegisterAPIGatewayProxy(t *testing.T) {
+	goLA := NewServe()
+	goLA.Route().SetEndpoint("/user/:user_id", &APIGatewayTestHandler{t: t})
+	response, err := goLA.RegisterAPIGatewayProxy(context.Background(), events.APIGatewayProxyRequest{
+		Path:                            "/user/123",
+		HTTPMethod:                      "GET",
+		Headers:                         map[string]string{"x-test": "value"},
+		MultiValueQueryStringParameters: map[string][]string{"page": {"1", "2"}},
+		RequestContext: events.APIGatewayProxyRequestContext{
+			Stage: "prod",
+			Authorizer: map[string]any{
+				"principalId": "principal",
+				"claims":      map[string]any{"sub": "user-1"},
+			},
+		},
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("GET")), response.Body)
+	assert.True(t, response.IsBase64Encoded)
+	assert.Equal(t, []string{"session=abc"}, response.MultiValueHeaders["Set-Cookie"])
+
+	response, err = goLA.RegisterAPIGatewayProxy(context.Background(), events.APIGatewayProxyRequest{Path: "/none", HTTPMethod: "GET"})
+	assert.Nil(t, err)
+	assert.Equal(t, 404, response.StatusCode)
+}
+
+type APIGatewayV2TestHandler struct {
+	DefaultHttpHandler
+	t *testing.T
+}
+
+func (h *APIGatewayV2TestHandler) Post(ctx context.Context, request Request, response Response) (er error) {
+	assert.Equal(h.t, "$default", request.Stage())
+	assert.Equal(h.t, "user-1", request.Claims()["sub"])
+	assert.Equal(h.t, "a=1; b=2", request.GetHeader("Cookie"))
+	assert.Equal(h.t, []string{"1", "2"}, request.QueryValues("page"))
+	assert.Equal(h.t, "a b", request.QueryValue("q"))
+	assert.Equal(h.t, "body", string(request.Body().Bytes()))
+	response.SetHeader("X-Multi", "1").AddHeader("X-Multi", "2")
+	response.SetCookie(http.Cookie{Name: "session", Value: "abc"})
+	response.SetBody(buf.NewByteBufString("POST"))
+	return
+}
+
+func TestGoLA_RegisterAPIGatewayV2HTTP(t *testing.T) {
+	goLA := NewServe()
+	goLA.Route().SetEndpoint("/user/:user_id", &APIGatewayV2TestHandler{t: t})
+	request := events.APIGatewayV2HTTPRequest{
+		RawPath:        "/user/123",
+		RawQueryString: "page=1&page=2&q=a%20b",
+		Cookies:        []string{"a=1", "b=2"},
+		Headers:        map[string]string{"content-type": "text/plain"},
+		Body:           base64.StdEncoding.EncodeToString([]byte("body")),
+		RequestContext: events.APIGatewayV2HTTPRequestContext{
+			Stage: "$default",
+			HTTP:  events.APIGatewayV2HTTPRequestContextHTTPDescription{Method: "POST", Path: "/user/123"},
+			Authorizer: &events.APIGatewayV2HTTPRequestContextAuthorizerDescription{
+				JWT: &events.APIGatewayV2HTTPRequestContextAuthorizerJWTDescription{Claims: map[string]string{"sub": "user-1"}},
+			},
+		},
+		IsBase64Encoded: true,
+	}
+
+	response, err := goLA.RegisterAPIGatewayV2HTTP(context.Background(), request)
+	assert.Nil(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("POST")), response.Body)
+	assert.Equal(t, "1, 2", response.Headers["X-Multi"])
+	assert.Equal(t, []string{"session=abc"}, response.Cookies)
+	assert.Empty(t, response.Headers["Set-Cookie"])
+}
@@ -0,0 +1,424 @@
+package gola
+
+import (
+	"fmt"
+	"net/mail"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	httpheadername "github.com/kklab-com/gone-httpheadername"
+	httpstatus "github.com/kklab-com/gone-httpstatus"
+	erresponse "github.com/kklab-com/goth-erresponse"
+	"github.com/kklab-com/goth-erresponse/constant"
+	kkerror "github.com/kklab-com/goth-kkerror"
+)
+
+// FieldError describes a field failed to bind or validate, it is listed in ErrorData()["fields"].
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+func fieldsErrorResponse(statusCode int, code string, description string, fields []FieldError) erresponse.ErrorResponse {
+	return &erresponse.DefaultErrorResponse{
+		StatusCode:  statusCode,
+		Name:        constant.ErrorInvalidRequest,
+		Description: description,
+		Data:        map[string]any{"fields": fields},
+		DefaultKKError: kkerror.DefaultKKError{
+			ErrorLevel:    kkerror.Normal,
+			ErrorCategory: kkerror.Client,
+			ErrorCode:     code,
+		},
+	}
+}
+
+// Bind fills v from JSON body and `path`, `query`, `header` tagged fields then validates it by `validate` tags,
+// fields can not be converted are reported as 400 and fields failed validation as 422.
+func (r *request) Bind(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("gola: Bind target must be a pointer to struct, got %T", v)
+	}
+
+	if len(r.Body().Bytes()) > 0 && strings.Contains(r.GetHeader(httpheadername.ContentType), "json") {
+		if err := r.BindJSON(v); err != nil {
+			return err
+		}
+	}
+
+	return r.bindFields(rv, "path", "query", "header")
+}
+
+// BindQuery fills `query` tagged fields of v from query string then validates it like Bind.
+func (r *request) BindQuery(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("gola: BindQuery target must be a pointer to struct, got %T", v)
+	}
+
+	return r.bindFields(rv, "query")
+}
+
+func (r *request) bindFields(rv reflect.Value, sources ...string) error {
+	bound, err := structFields(rv.Elem().Type())
+	if err != nil {
+		return err
+	}
+
+	var fields []FieldError
+	for _, field := range bound {
+		values := r.bindValues(field, sources)
+		if len(values) == 0 {
+			continue
+		}
+
+		value, err := rv.Elem().FieldByIndexErr(field.index)
+		if err != nil {
+			continue
+		}
+
+		if err := setValue(value, values); err != nil {
+			fields = append(fields, FieldError{Field: field.name, Rule: "type", Message: err.Error()})
+		}
+	}
+
+	if len(fields) > 0 {
+		return fieldsErrorResponse(httpstatus.BadRequest, "400201", "invalid field", fields)
+	}
+
+	return Validate(rv.Interface())
+}
+
+func (r *request) bindValues(field boundField, sources []string) []string {
+	for _, source := range sources {
+		if source == field.source {
+			return r.sourceValues(field)
+		}
+	}
+
+	return nil
+}
+
+func (r *request) sourceValues(field boundField) []string {
+	switch field.source {
+	case "path":
+		if v, f := r.pathParameters[field.key]; f {
+			return []string{v}
+		}
+	case "query":
+		return r.QueryValues(field.key)
+	case "header":
+		return r.GetHeaders(field.key)
+	}
+
+	return nil
+}
+
+// Validate checks `validate` tags of struct v, rules are separated by comma:
+// required, min=N, max=N, email, enum=a|b|c and regex=PATTERN which must be the last rule.
+// min and max compare number value or length of string, slice and map.
+func Validate(v any) error {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+
+	bound, err := structFields(rv.Type())
+	if err != nil {
+		return err
+	}
+
+	var fields []FieldError
+	for _, field := range bound {
+		value, err := rv.FieldByIndexErr(field.index)
+		if err != nil {
+			continue
+		}
+
+		for _, rule := range field.rules {
+			if msg := rule.check(value); msg != "" {
+				fields = append(fields, FieldError{Field: field.name, Rule: rule.name, Message: msg})
+				break
+			}
+		}
+	}
+
+	if len(fields) > 0 {
+		return fieldsErrorResponse(httpstatus.UnprocessableEntity, "422001", "validation failed", fields)
+	}
+
+	return nil
+}
+
+type boundField struct {
+	index  []int
+	name   string
+	source string
+	key    string
+	rules  []validateRule
+}
+
+type cachedFields struct {
+	fields []boundField
+	err    error
+}
+
+var boundFieldsCache sync.Map
+
+// checkTags reports invalid `validate` tags of struct or struct pointer type t.
+func checkTags(t reflect.Type) error {
+	if t == nil {
+		return nil
+	}
+
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	_, err := structFields(t)
+	return err
+}
+
+func structFields(t reflect.Type) ([]boundField, error) {
+	if v, ok := boundFieldsCache.Load(t); ok {
+		return v.(cachedFields).fields, v.(cachedFields).err
+	}
+
+	var fields []boundField
+	var err error
+	for _, sf := range reflect.VisibleFields(t) {
+		if !sf.IsExported() || sf.Anonymous {
+			continue
+		}
+
+		field := boundField{index: sf.Index, name: sf.Name}
+		if v := strings.Split(sf.Tag.Get("json"), ",")[0]; v != "" && v != "-" {
+			field.name = v
+		}
+
+		for _, source := range []string{"path", "query", "header"} {
+			if v := sf.Tag.Get(source); v != "" {
+				field.name, field.source, field.key = v, source, v
+				break
+			}
+		}
+
+		if field.rules, err = parseRules(sf.Tag.Get("validate")); err != nil {
+			err = fmt.Errorf("gola: field %s of %s: %w", sf.Name, t, err)
+			break
+		}
+
+		fields = append(fields, field)
+	}
+
+	boundFieldsCache.Store(t, cachedFields{fields: fields, err: err})
+	return fields, err
+}
+
+func setValue(value reflect.Value, values []string) error {
+	if value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			value.Set(reflect.New(value.Type().Elem()))
+		}
+
+		value = value.Elem()
+	}
+
+	if value.Kind() == reflect.Slice {
+		slice := reflect.MakeSlice(value.Type(), len(values), len(values))
+		for i, v := range values {
+			if err := setScalar(slice.Index(i), v); err != nil {
+				return err
+			}
+		}
+
+		value.Set(slice)
+		return nil
+	}
+
+	return setScalar(value, values[0])
+}
+
+func setScalar(value reflect.Value, v string) error {
+	if value.Type() == reflect.TypeOf(time.Time{}) {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return fmt.Errorf("must be a RFC 3339 time")
+		}
+
+		value.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(v)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("must be a boolean")
+		}
+
+		value.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(v, 10, value.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("must be an integer")
+		}
+
+		value.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(v, 10, value.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("must be a non-negative integer")
+		}
+
+		value.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(v, value.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("must be a number")
+		}
+
+		value.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %s", value.Type())
+	}
+
+	return nil
+}
+
+type validateRule struct {
+	name  string
+	check func(value reflect.Value) string
+}
+
+func parseRules(tag string) ([]validateRule, error) {
+	var rules []validateRule
+	for tag != "" {
+		rule, rest, _ := strings.Cut(tag, ",")
+		name, arg, _ := strings.Cut(rule, "=")
+		if name == "regex" {
+			_, arg, _ = strings.Cut(tag, "=")
+			rest = ""
+		}
+
+		v, err := newRule(name, arg)
+		if err != nil {
+			return nil, err
+		}
+
+		rules = append(rules, v)
+		tag = rest
+	}
+
+	return rules, nil
+}
+
+func newRule(name string, arg string) (validateRule, error) {
+	rule := validateRule{name: name}
+	switch name {
+	case "required":
+		rule.check = func(value reflect.Value) string {
+			if value.IsZero() {
+				return "is required"
+			}
+
+			return ""
+		}
+	case "min", "max":
+		limit, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return rule, fmt.Errorf("invalid %s rule %q", name, arg)
+		}
+
+		rule.check = func(value reflect.Value) string {
+			n, ok := measure(value)
+			if !ok || (name == "min" && n >= limit) || (name == "max" && n <= limit) {
+				return ""
+			}
+
+			if name == "min" {
+				return "must be at least " + arg
+			}
+
+			return "must be at most " + arg
+		}
+	case "email":
+		rule.check = stringRule(func(s string) string {
+			if addr, err := mail.ParseAddress(s); err != nil || addr.Address != s {
+				return "must be an email"
+			}
+
+			return ""
+		})
+	case "enum":
+		options := strings.Split(arg, "|")
+		rule.check = stringRule(func(s string) string {
+			for _, option := range options {
+				if s == option {
+					return ""
+				}
+			}
+
+			return "must be one of " + strings.Join(options, ", ")
+		})
+	case "regex":
+		pattern, err := regexp.Compile(arg)
+		if err != nil {
+			return rule, fmt.Errorf("invalid regex rule %q: %w", arg, err)
+		}
+
+		rule.check = stringRule(func(s string) string {
+			if !pattern.MatchString(s) {
+				return "must match " + arg
+			}
+
+			return ""
+		})
+	default:
+		return rule, fmt.Errorf("unknown validate rule %q", name)
+	}
+
+	return rule, nil
+}
+
+// stringRule applies check to non-empty string, emptiness is the concern of required.
+func stringRule(check func(s string) string) func(value reflect.Value) string {
+	return func(value reflect.Value) string {
+		value = reflect.Indirect(value)
+		if value.Kind() != reflect.String || value.String() == "" {
+			return ""
+		}
+
+		return check(value.String())
+	}
+}
+
+func measure(value reflect.Value) (float64, bool) {
+	value = reflect.Indirect(value)
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), true
+	case reflect.String:
+		return float64(len([]rune(value.String()))), true
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return float64(value.Len()), true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,127 @@
+package gola
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	erresponse "github.com/kklab-com/goth-erresponse"
+	"github.com/stretchr/testify/assert"
+)
+
+type GOLATestBindInput struct {
+	UserID string   `path:"user_id" validate:"required,regex=^[0-9]+$"`
+	Page   int      `query:"page" validate:"min=1,max=100"`
+	Tags   []string `query:"tag" validate:"max=2"`
+	Tenant string   `header:"X-Tenant" validate:"required"`
+	Name   string   `json:"name" validate:"required,min=2"`
+	Email  string   `json:"email" validate:"email"`
+	Role   string   `json:"role" validate:"enum=admin|member"`
+}
+
+type GOLATestBindHandler struct {
+	DefaultHttpHandler
+}
+
+func (h *GOLATestBindHandler) NewInput(method string) any {
+	if method == "PUT" {
+		return &GOLATestBindInput{}
+	}
+
+	return nil
+}
+
+func (h *GOLATestBindHandler) Put(ctx context.Context, request Request, response Response) (er error) {
+	return response.JSON(200, h.Input(ctx))
+}
+
+func TestRequest_Bind(t *testing.T) {
+	goLA := NewServe()
+	goLA.Route().SetEndpoint("/user/:user_id", &GOLATestBindHandler{})
+	put := func(path string, query map[string][]string, body string) (events.ALBTargetGroupResponse, map[string]any) {
+		response, err := goLA.Register(context.Background(), events.ALBTargetGroupRequest{Path: path, HTTPMethod: "PUT", Body: body,
+			MultiValueQueryStringParameters: query,
+			MultiValueHeaders:               map[string][]string{"Content-Type": {"application/json"}, "X-Tenant": {"kk"}}})
+		assert.Nil(t, err)
+		raw, _ := base64.StdEncoding.DecodeString(response.Body)
+		doc := map[string]any{}
+		json.Unmarshal(raw, &doc)
+		return response, doc
+	}
+
+	response, doc := put("/user/123", map[string][]string{"page": {"2"}, "tag": {"a", "b"}}, `{"name":"kk","email":"kk@example.com","role":"admin"}`)
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, "123", doc["UserID"])
+	assert.EqualValues(t, 2, doc["Page"])
+	assert.Equal(t, []any{"a", "b"}, doc["Tags"])
+	assert.Equal(t, "kk", doc["Tenant"])
+	assert.Equal(t, "kk", doc["name"])
+
+	response, doc = put("/user/123", map[string][]string{"page": {"two"}}, `{"name":"kk"}`)
+	assert.Equal(t, 400, response.StatusCode)
+	assert.Equal(t, []any{map[string]any{"field": "page", "rule": "type", "message": "must be an integer"}}, doc["data"].(map[string]any)["fields"])
+
+	response, doc = put("/user/abc", map[string][]string{"page": {"0"}, "tag": {"a", "b", "c"}}, `{"name":"k","email":"kk","role":"root"}`)
+	assert.Equal(t, 422, response.StatusCode)
+	var fields []string
+	for _, field := range doc["data"].(map[string]any)["fields"].([]any) {
+		fields = append(fields, field.(map[string]any)["field"].(string))
+	}
+
+	assert.Equal(t, []string{"user_id", "page", "tag", "name", "email", "role"}, fields)
+}
+
+func TestValidate(t *testing.T) {
+	assert.Nil(t, Validate(&struct {
+		Name *string `validate:"min=2"`
+	}{}))
+
+	err := Validate(struct {
+		Name string `validate:"required"`
+	}{})
+	assert.Equal(t, 422, err.(erresponse.ErrorResponse).ErrorStatusCode())
+	assert.Equal(t, []FieldError{{Field: "Name", Rule: "required", Message: "is required"}}, err.(erresponse.ErrorResponse).ErrorData()["fields"])
+
+	err = Validate(struct {
+		Name string `validate:"unknown"`
+	}{})
+	assert.NotNil(t, err)
+	_, ok := err.(erresponse.ErrorResponse)
+	assert.False(t, ok)
+}
+
+type GOLATestBadInput struct {
+	Name string `json:"name" validate:"regex=[a-"`
+}
+
+type GOLATestBadInputHandler struct {
+	DefaultHttpHandler
+}
+
+func (h *GOLATestBadInputHandler) NewInput(method string) any {
+	if method == "POST" {
+		return &GOLATestBadInput{}
+	}
+
+	return nil
+}
+
+func TestCheckTags(t *testing.T) {
+	assert.Nil(t, checkTags(reflect.TypeOf(&GOLATestBindInput{})))
+	assert.Nil(t, checkTags(reflect.TypeOf("")))
+	assert.NotNil(t, checkTags(reflect.TypeOf(GOLATestBadInput{})))
+	assert.NotNil(t, checkTags(reflect.TypeOf(struct {
+		Page int `validate:"min=one"`
+	}{})))
+
+	route := NewRoute()
+	assert.Panics(t, func() { route.SetEndpoint("/bad", &GOLATestBadInputHandler{}) })
+	assert.Panics(t, func() { route.Group("/group").Get("/bad", &GOLATestBadInputHandler{}) })
+	assert.NotPanics(t, func() { route.SetEndpoint("/user/:user_id", &GOLATestBindHandler{}) })
+	assert.Panics(t, func() {
+		Typed(func(ctx context.Context, in *GOLATestBadInput) (any, error) { return nil, nil })
+	})
+}
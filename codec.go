@@ -0,0 +1,87 @@
+package gola
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	httpstatus "github.com/kklab-com/gone-httpstatus"
+	erresponse "github.com/kklab-com/goth-erresponse"
+	"github.com/kklab-com/goth-erresponse/constant"
+	kkerror "github.com/kklab-com/goth-kkerror"
+)
+
+// Codec encodes Response.JSON and decodes Request.BindJSON, strict decoding rejects unknown fields.
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any, strict bool) error
+}
+
+// JSONCodec is the encoding/json Codec, HTML is not escaped unless EscapeHTML is set.
+type JSONCodec struct {
+	EscapeHTML bool
+	Prefix     string
+	Indent     string
+}
+
+func (c *JSONCodec) Marshal(v any) ([]byte, error) {
+	buffer := &bytes.Buffer{}
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(c.EscapeHTML)
+	encoder.SetIndent(c.Prefix, c.Indent)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimRight(buffer.Bytes(), "\n"), nil
+}
+
+func (c *JSONCodec) Unmarshal(data []byte, v any, strict bool) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if strict {
+		decoder.DisallowUnknownFields()
+	}
+
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+
+	if _, err := decoder.Token(); err != io.EOF {
+		return erresponse.InvalidRequestWithMessage("unexpected data after json value")
+	}
+
+	return nil
+}
+
+var DefaultCodec Codec = &JSONCodec{}
+
+var PayloadTooLarge erresponse.ErrorResponse = &erresponse.DefaultErrorResponse{
+	StatusCode:  httpstatus.PayloadTooLarge,
+	Name:        constant.ErrorInvalidRequest,
+	Description: "payload too large",
+	DefaultKKError: kkerror.DefaultKKError{
+		ErrorLevel:    kkerror.Normal,
+		ErrorCategory: kkerror.Client,
+		ErrorCode:     "413001",
+	},
+}
+
+// BindOptions controls Request.BindJSON and Request.Form, limit of 0 is unlimited.
+type BindOptions struct {
+	Strict      bool
+	MaxBodySize int
+	MaxFileSize int
+}
+
+type binding struct {
+	codec   Codec
+	options BindOptions
+}
+
+func (g *GoLA) binding() binding {
+	if g.Codec == nil {
+		return binding{codec: DefaultCodec, options: g.BindOptions}
+	}
+
+	return binding{codec: g.Codec, options: g.BindOptions}
+}
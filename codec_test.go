@@ -0,0 +1,61 @@
+package gola
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+type GOLATestUser struct {
+	Name string `json:"name"`
+}
+
+type GOLATestCustomCodec struct {
+	JSONCodec
+}
+
+func (c *GOLATestCustomCodec) Marshal(v any) ([]byte, error) {
+	return []byte(`"custom"`), nil
+}
+
+func TestRequest_BindJSON(t *testing.T) {
+	goLA := NewServe()
+	goLA.BindOptions = BindOptions{Strict: true, MaxBodySize: 32}
+	goLA.Route().Post("/user", HandlerFunc(func(ctx context.Context, request Request, response Response) error {
+		user := &GOLATestUser{}
+		if err := request.BindJSON(user); err != nil {
+			return err
+		}
+
+		return response.JSON(201, user)
+	}))
+
+	post := func(body string) events.ALBTargetGroupResponse {
+		response, err := goLA.Register(context.Background(), events.ALBTargetGroupRequest{Path: "/user", HTTPMethod: "POST", Body: body})
+		assert.Nil(t, err)
+		return response
+	}
+
+	response := post(`{"name":"<kk>"}`)
+	assert.Equal(t, 201, response.StatusCode)
+	assert.Equal(t, []string{"application/json"}, response.MultiValueHeaders["Content-Type"])
+	body, _ := base64.StdEncoding.DecodeString(response.Body)
+	assert.Equal(t, `{"name":"<kk>"}`, string(body))
+
+	assert.Equal(t, 400, post(`{"name":"kk","age":1}`).StatusCode)
+	assert.Equal(t, 400, post(`{"name":`).StatusCode)
+	assert.Equal(t, 400, post(`{"name":"kk"}{}`).StatusCode)
+	assert.Equal(t, 400, post(``).StatusCode)
+	assert.Equal(t, 413, post(`{"name":"0123456789012345678901234567890"}`).StatusCode)
+
+	goLA.BindOptions = BindOptions{}
+	assert.Equal(t, 201, post(`{"name":"kk","age":1}`).StatusCode)
+
+	goLA.Codec = &GOLATestCustomCodec{}
+	response = post(`{"name":"kk"}`)
+	body, _ = base64.StdEncoding.DecodeString(response.Body)
+	assert.Equal(t, `"custom"`, string(body))
+}
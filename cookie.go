@@ -0,0 +1,157 @@
+package gola
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+var ErrInvalidCookie = errors.New("gola: invalid cookie")
+
+func (r *request) Cookie(name string) *http.Cookie {
+	for _, cookie := range r.Cookies() {
+		if cookie.Name == name {
+			return cookie
+		}
+	}
+
+	return nil
+}
+
+func (r *request) Cookies() []*http.Cookie {
+	return (&http.Request{Header: r.Header()}).Cookies()
+}
+
+// CookieCodec protects cookie value, the cookie name is bound so value can not be moved to another cookie.
+type CookieCodec interface {
+	Encode(name string, value string) (string, error)
+	Decode(name string, encoded string) (string, error)
+}
+
+// SignedCookie signs value by HMAC-SHA256, value is readable by client.
+// Keys[0] signs, all Keys verify so old keys can be kept during rotation.
+type SignedCookie struct {
+	Keys [][]byte
+}
+
+func (s *SignedCookie) Encode(name string, value string) (string, error) {
+	if len(s.Keys) == 0 {
+		return "", errors.New("gola: SignedCookie has no key")
+	}
+
+	payload := base64.RawURLEncoding.EncodeToString([]byte(value))
+	return payload + "." + base64.RawURLEncoding.EncodeToString(s.sign(s.Keys[0], name, payload)), nil
+}
+
+func (s *SignedCookie) Decode(name string, encoded string) (string, error) {
+	payload, signature, found := strings.Cut(encoded, ".")
+	if !found {
+		return "", ErrInvalidCookie
+	}
+
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return "", ErrInvalidCookie
+	}
+
+	for _, key := range s.Keys {
+		if hmac.Equal(mac, s.sign(key, name, payload)) {
+			value, err := base64.RawURLEncoding.DecodeString(payload)
+			if err != nil {
+				return "", ErrInvalidCookie
+			}
+
+			return string(value), nil
+		}
+	}
+
+	return "", ErrInvalidCookie
+}
+
+func (s *SignedCookie) sign(key []byte, name string, payload string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(name + "|" + payload))
+	return h.Sum(nil)
+}
+
+// EncryptedCookie encrypts value by AES-GCM, key length must be 16, 24 or 32 bytes.
+// Keys[0] encrypts, all Keys decrypt so old keys can be kept during rotation.
+type EncryptedCookie struct {
+	Keys [][]byte
+}
+
+func (e *EncryptedCookie) Encode(name string, value string) (string, error) {
+	if len(e.Keys) == 0 {
+		return "", errors.New("gola: EncryptedCookie has no key")
+	}
+
+	aead, err := newGCM(e.Keys[0])
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(aead.Seal(nonce, nonce, []byte(value), []byte(name))), nil
+}
+
+func (e *EncryptedCookie) Decode(name string, encoded string) (string, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", ErrInvalidCookie
+	}
+
+	for _, key := range e.Keys {
+		aead, err := newGCM(key)
+		if err != nil || len(data) < aead.NonceSize() {
+			continue
+		}
+
+		if value, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], []byte(name)); err == nil {
+			return string(value), nil
+		}
+	}
+
+	return "", ErrInvalidCookie
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// SetSecureCookie encodes cookie.Value by codec and sets cookie into response.
+func SetSecureCookie(response Response, codec CookieCodec, cookie http.Cookie) error {
+	value, err := codec.Encode(cookie.Name, cookie.Value)
+	if err != nil {
+		return err
+	}
+
+	cookie.Value = value
+	response.SetCookie(cookie)
+	return nil
+}
+
+// SecureCookie returns the value of cookie decoded by codec, http.ErrNoCookie when it is absent
+// and ErrInvalidCookie when it is forged or encoded by unknown key.
+func SecureCookie(request Request, codec CookieCodec, name string) (string, error) {
+	cookie := request.Cookie(name)
+	if cookie == nil {
+		return "", http.ErrNoCookie
+	}
+
+	return codec.Decode(name, cookie.Value)
+}
@@ -0,0 +1,65 @@
+package gola
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequest_Cookie(t *testing.T) {
+	req := NewRequest(events.ALBTargetGroupRequest{MultiValueHeaders: map[string][]string{"Cookie": {"a=1; b=2", "c=3"}}}, nil)
+	assert.Equal(t, "2", req.Cookie("b").Value)
+	assert.Equal(t, "3", req.Cookie("c").Value)
+	assert.Nil(t, req.Cookie("d"))
+	assert.Len(t, req.Cookies(), 3)
+
+	req = NewAPIGatewayV2HTTPRequest(events.APIGatewayV2HTTPRequest{RawPath: "/", Cookies: []string{"a=1", "b=2"}}, nil)
+	assert.Equal(t, "1", req.Cookie("a").Value)
+	assert.Equal(t, "2", req.Cookie("b").Value)
+}
+
+func TestSecureCookie(t *testing.T) {
+	oldKey, newKey := []byte(strings.Repeat("o", 32)), []byte(strings.Repeat("n", 32))
+	for _, codec := range []CookieCodec{&SignedCookie{Keys: [][]byte{oldKey}}, &EncryptedCookie{Keys: [][]byte{oldKey}}} {
+		response := NewResponse()
+		assert.Nil(t, SetSecureCookie(response, codec, http.Cookie{Name: "session", Value: "user-1", HttpOnly: true}))
+		encoded := response.Cookie("session").Value
+		assert.NotEqual(t, "user-1", encoded)
+
+		request := NewRequest(events.ALBTargetGroupRequest{MultiValueHeaders: map[string][]string{"Cookie": {"session=" + encoded + "; other=" + encoded}}}, nil)
+		value, err := SecureCookie(request, codec, "session")
+		assert.Nil(t, err)
+		assert.Equal(t, "user-1", value)
+
+		_, err = SecureCookie(request, codec, "other")
+		assert.Equal(t, ErrInvalidCookie, err)
+		_, err = SecureCookie(request, codec, "none")
+		assert.Equal(t, http.ErrNoCookie, err)
+		_, err = codec.Decode("session", encoded[:len(encoded)-2]+"xx")
+		assert.Equal(t, ErrInvalidCookie, err)
+
+		switch c := codec.(type) {
+		case *SignedCookie:
+			c.Keys = [][]byte{newKey, oldKey}
+		case *EncryptedCookie:
+			c.Keys = [][]byte{newKey, oldKey}
+		}
+
+		value, err = SecureCookie(request, codec, "session")
+		assert.Nil(t, err)
+		assert.Equal(t, "user-1", value)
+		rotated, _ := codec.Encode("session", "user-1")
+		switch c := codec.(type) {
+		case *SignedCookie:
+			c.Keys = [][]byte{oldKey}
+		case *EncryptedCookie:
+			c.Keys = [][]byte{oldKey}
+		}
+
+		_, err = codec.Decode("session", rotated)
+		assert.Equal(t, ErrInvalidCookie, err)
+	}
+}
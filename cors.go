@@ -0,0 +1,180 @@
+package gola
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	httpheadername "github.com/kklab-com/gone-httpheadername"
+)
+
+const (
+	headerAccessControlMaxAge        = "access-control-max-age"
+	headerAccessControlExposeHeaders = "access-control-expose-headers"
+)
+
+var defaultCORSMethods = []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// CORS is a cross-origin policy, it can be registered as Handler or as Middleware.
+//
+// AllowOrigins accepts exact origins, "*" and wildcard subdomains like "https://*.example.com",
+// AllowOriginPatterns are matched against the whole origin.
+// AllowHeaders of "*" reflects the requested headers, empty AllowMethods allows the simple methods.
+type CORS struct {
+	AllowOrigins        []string
+	AllowOriginPatterns []*regexp.Regexp
+	AllowMethods        []string
+	AllowHeaders        []string
+	ExposeHeaders       []string
+	AllowCredentials    bool
+	MaxAge              time.Duration
+}
+
+// IsPreflight reports whether request is a CORS preflight request.
+func IsPreflight(request Request) bool {
+	return request.Method() == http.MethodOptions &&
+		request.GetHeader(httpheadername.Origin) != "" &&
+		request.GetHeader(httpheadername.AccessControlRequestMethod) != ""
+}
+
+func (c *CORS) AllowOrigin(origin string) bool {
+	if origin == "" {
+		return false
+	}
+
+	for _, allow := range c.AllowOrigins {
+		if allow == "*" || strings.EqualFold(allow, origin) {
+			return true
+		}
+
+		if i := strings.Index(allow, "://*."); i > 0 {
+			scheme, suffix := allow[:i+3], allow[i+4:]
+			if len(origin) > len(scheme)+len(suffix) &&
+				strings.EqualFold(origin[:len(scheme)], scheme) &&
+				strings.HasSuffix(strings.ToLower(origin), strings.ToLower(suffix)) {
+				return true
+			}
+		}
+	}
+
+	for _, pattern := range c.AllowOriginPatterns {
+		if loc := pattern.FindStringIndex(origin); loc != nil && loc[0] == 0 && loc[1] == len(origin) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *CORS) anyOrigin() bool {
+	for _, allow := range c.AllowOrigins {
+		if allow == "*" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Apply sets CORS headers of request into response, it returns false when origin is not allowed.
+func (c *CORS) Apply(request Request, response Response) bool {
+	origin := request.GetHeader(httpheadername.Origin)
+	wildcard := c.anyOrigin() && !c.AllowCredentials
+	if !wildcard {
+		response.AddHeader(httpheadername.Vary, "Origin")
+	}
+
+	if !c.AllowOrigin(origin) {
+		return false
+	}
+
+	if wildcard {
+		response.SetHeader(httpheadername.AccessControlAllowOrigin, "*")
+	} else {
+		response.SetHeader(httpheadername.AccessControlAllowOrigin, origin)
+	}
+
+	if c.AllowCredentials {
+		response.SetHeader(httpheadername.AccessControlAllowCredentials, "true")
+	}
+
+	if !IsPreflight(request) {
+		if len(c.ExposeHeaders) > 0 {
+			response.SetHeader(headerAccessControlExposeHeaders, strings.Join(c.ExposeHeaders, ", "))
+		}
+
+		return true
+	}
+
+	methods := c.AllowMethods
+	if len(methods) == 0 {
+		methods = defaultCORSMethods
+	}
+
+	response.SetHeader(httpheadername.AccessControlAllowMethods, strings.Join(methods, ", "))
+	if headers := c.allowHeaders(request); headers != "" {
+		response.SetHeader(httpheadername.AccessControlAllowHeaders, headers)
+	}
+
+	if c.MaxAge > 0 {
+		response.SetHeader(headerAccessControlMaxAge, strconv.Itoa(int(c.MaxAge.Seconds())))
+	}
+
+	return true
+}
+
+func (c *CORS) allowHeaders(request Request) string {
+	for _, header := range c.AllowHeaders {
+		if header == "*" {
+			return request.GetHeader(httpheadername.AccessControlRequestHeaders)
+		}
+	}
+
+	return strings.Join(c.AllowHeaders, ", ")
+}
+
+// Run applies policy, preflight request is answered by 204 and ErrHandled skips the handlers after it.
+func (c *CORS) Run(ctx context.Context, request Request, response Response) error {
+	c.Apply(request, response)
+	if IsPreflight(request) {
+		response.SetStatusCode(http.StatusNoContent)
+		return ErrHandled
+	}
+
+	return nil
+}
+
+// preflightHandlers returns CORS handlers and middlewares of the method preflight request asks for,
+// so an endpoint without OPTIONS handler answers preflight by its own policy instead of 405.
+func preflightHandlers(node *_Node, request Request) ([]Handler, []Middleware) {
+	handlers, middlewares, allow := nodeHandlers(node, strings.ToUpper(request.GetHeader(httpheadername.AccessControlRequestMethod)))
+	if allow != nil {
+		return nil, nil
+	}
+
+	var policies []Handler
+	for _, handler := range handlers {
+		if policy, ok := handler.(*CORS); ok {
+			policies = append(policies, policy)
+		}
+	}
+
+	return policies, middlewares
+}
+
+// Middleware applies policy, preflight request is answered by 204 without invoking next.
+func (c *CORS) Middleware(next Handler) Handler {
+	return HandlerFunc(func(ctx context.Context, request Request, response Response) error {
+		if IsPreflight(request) {
+			c.Apply(request, response)
+			response.SetStatusCode(http.StatusNoContent)
+			return nil
+		}
+
+		c.Apply(request, response)
+		return next.Run(ctx, request, response)
+	})
+}
@@ -0,0 +1,131 @@
+package gola
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCORS_AllowOrigin(t *testing.T) {
+	cors := &CORS{
+		AllowOrigins:        []string{"https://app.example.com", "https://*.example.org"},
+		AllowOriginPatterns: []*regexp.Regexp{regexp.MustCompile(`http://localhost:\d+`)},
+	}
+
+	assert.True(t, cors.AllowOrigin("https://app.example.com"))
+	assert.False(t, cors.AllowOrigin("https://evil.example.com"))
+	assert.True(t, cors.AllowOrigin("https://a.b.example.org"))
+	assert.False(t, cors.AllowOrigin("https://example.org"))
+	assert.False(t, cors.AllowOrigin("http://a.example.org"))
+	assert.False(t, cors.AllowOrigin("https://evilexample.org"))
+	assert.True(t, cors.AllowOrigin("http://localhost:3000"))
+	assert.False(t, cors.AllowOrigin("http://localhost:3000.evil.com"))
+	assert.False(t, cors.AllowOrigin(""))
+}
+
+func TestCORS_Middleware(t *testing.T) {
+	goLA := NewServe()
+	goLA.Use((&CORS{
+		AllowOrigins:     []string{"https://*.example.com"},
+		AllowHeaders:     []string{"Authorization", "Content-Type"},
+		ExposeHeaders:    []string{"X-Request-Id"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	}).Middleware)
+
+	invoked := 0
+	goLA.Route().Get("/user", HandlerFunc(func(ctx context.Context, request Request, response Response) error {
+		invoked++
+		return nil
+	}))
+
+	response, err := goLA.Register(context.Background(), events.ALBTargetGroupRequest{Path: "/user", HTTPMethod: "OPTIONS",
+		MultiValueHeaders: map[string][]string{"Origin": {"https://app.example.com"}, "Access-Control-Request-Method": {"GET"}}})
+	assert.Nil(t, err)
+	assert.Equal(t, 204, response.StatusCode)
+	assert.Equal(t, 0, invoked)
+	assert.Equal(t, []string{"https://app.example.com"}, response.MultiValueHeaders["Access-Control-Allow-Origin"])
+	assert.Equal(t, []string{"true"}, response.MultiValueHeaders["Access-Control-Allow-Credentials"])
+	assert.Equal(t, []string{"Authorization, Content-Type"}, response.MultiValueHeaders["Access-Control-Allow-Headers"])
+	assert.Equal(t, []string{"600"}, response.MultiValueHeaders["Access-Control-Max-Age"])
+	assert.Equal(t, []string{"Origin"}, response.MultiValueHeaders["Vary"])
+
+	response, err = goLA.Register(context.Background(), events.ALBTargetGroupRequest{Path: "/user", HTTPMethod: "GET",
+		MultiValueHeaders: map[string][]string{"Origin": {"https://app.example.com"}}})
+	assert.Nil(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, 1, invoked)
+	assert.Equal(t, []string{"X-Request-Id"}, response.MultiValueHeaders["Access-Control-Expose-Headers"])
+	assert.Nil(t, response.MultiValueHeaders["Access-Control-Allow-Methods"])
+
+	response, err = goLA.Register(context.Background(), events.ALBTargetGroupRequest{Path: "/none", HTTPMethod: "GET",
+		MultiValueHeaders: map[string][]string{"Origin": {"https://evil.com"}}})
+	assert.Nil(t, err)
+	assert.Equal(t, 404, response.StatusCode)
+	assert.Nil(t, response.MultiValueHeaders["Access-Control-Allow-Origin"])
+	assert.Equal(t, []string{"Origin"}, response.MultiValueHeaders["Vary"])
+}
+
+func TestCORS_Run(t *testing.T) {
+	goLA := NewServe()
+	invoked := 0
+	goLA.Route().SetEndpoint("/public", &CORS{AllowOrigins: []string{"*"}}, HandlerFunc(func(ctx context.Context, request Request, response Response) error {
+		invoked++
+		response.SetStatusCode(200)
+		return nil
+	}))
+
+	response, err := goLA.Register(context.Background(), events.ALBTargetGroupRequest{Path: "/public", HTTPMethod: "OPTIONS",
+		MultiValueHeaders: map[string][]string{"Origin": {"https://any.com"}, "Access-Control-Request-Method": {"PUT"}}})
+	assert.Nil(t, err)
+	assert.Equal(t, 204, response.StatusCode)
+	assert.Equal(t, []string{"*"}, response.MultiValueHeaders["Access-Control-Allow-Origin"])
+	assert.Equal(t, []string{"GET, HEAD, POST, PUT, PATCH, DELETE"}, response.MultiValueHeaders["Access-Control-Allow-Methods"])
+	assert.Nil(t, response.MultiValueHeaders["Vary"])
+	assert.Equal(t, 0, invoked)
+
+	response, err = goLA.Register(context.Background(), events.ALBTargetGroupRequest{Path: "/public", HTTPMethod: "GET",
+		MultiValueHeaders: map[string][]string{"Origin": {"https://any.com"}}})
+	assert.Nil(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, 1, invoked)
+}
+
+func TestCORS_MethodRoute(t *testing.T) {
+	goLA := NewServe()
+	cors := &CORS{AllowOrigins: []string{"https://app.example.com"}}
+	invoked := 0
+	handler := HandlerFunc(func(ctx context.Context, request Request, response Response) error {
+		invoked++
+		return nil
+	})
+
+	goLA.Route().Group("/api").Use(cors.Middleware).Get("/user", handler)
+	goLA.Route().Post("/book", cors, handler)
+	goLA.Route().Get("/plain", handler)
+	preflight := func(path string, method string) events.ALBTargetGroupResponse {
+		response, err := goLA.Register(context.Background(), events.ALBTargetGroupRequest{Path: path, HTTPMethod: "OPTIONS",
+			MultiValueHeaders: map[string][]string{"Origin": {"https://app.example.com"}, "Access-Control-Request-Method": {method}}})
+		assert.Nil(t, err)
+		return response
+	}
+
+	for _, response := range []events.ALBTargetGroupResponse{preflight("/api/user", "GET"), preflight("/book", "POST")} {
+		assert.Equal(t, 204, response.StatusCode)
+		assert.Equal(t, []string{"https://app.example.com"}, response.MultiValueHeaders["Access-Control-Allow-Origin"])
+		assert.Nil(t, response.MultiValueHeaders["Allow"])
+	}
+
+	assert.Equal(t, 0, invoked)
+	response := preflight("/book", "PUT")
+	assert.Equal(t, 405, response.StatusCode)
+	assert.Equal(t, []string{"POST"}, response.MultiValueHeaders["Allow"])
+	response = preflight("/plain", "GET")
+	assert.Equal(t, 405, response.StatusCode)
+	assert.Equal(t, []string{"GET, HEAD"}, response.MultiValueHeaders["Allow"])
+	assert.Equal(t, 0, invoked)
+}
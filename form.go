@@ -0,0 +1,162 @@
+package gola
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"net/url"
+
+	httpheadername "github.com/kklab-com/gone-httpheadername"
+	erresponse "github.com/kklab-com/goth-erresponse"
+)
+
+// Form is the parsed urlencoded or multipart body, multipart parts without filename are put into Values.
+type Form struct {
+	Values url.Values
+	Files  map[string][]*FormFile
+}
+
+type FormFile struct {
+	FieldName   string
+	Filename    string
+	ContentType string
+	Header      textproto.MIMEHeader
+	Size        int64
+	data        []byte
+}
+
+func (f *FormFile) Reader() io.Reader {
+	return bytes.NewReader(f.data)
+}
+
+func (f *FormFile) Bytes() []byte {
+	return f.data
+}
+
+func (r *request) Form() (*Form, error) {
+	if r.form == nil && r.formErr == nil {
+		r.form, r.formErr = r.parseForm()
+	}
+
+	return r.form, r.formErr
+}
+
+func (r *request) FormValue(name string) string {
+	if v := r.FormValues(name); len(v) > 0 {
+		return v[0]
+	}
+
+	return ""
+}
+
+func (r *request) FormValues(name string) []string {
+	if form, err := r.Form(); err == nil {
+		return form.Values[name]
+	}
+
+	return nil
+}
+
+func (r *request) FormFile(name string) (*FormFile, error) {
+	form, err := r.Form()
+	if err != nil {
+		return nil, err
+	}
+
+	if files := form.Files[name]; len(files) > 0 {
+		return files[0], nil
+	}
+
+	return nil, erresponse.InvalidRequestInvalidDataOfName(name)
+}
+
+func (r *request) parseForm() (*Form, error) {
+	form := &Form{Values: url.Values{}, Files: map[string][]*FormFile{}}
+	contentType := r.GetHeader(httpheadername.ContentType)
+	if contentType == "" {
+		return form, nil
+	}
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, erresponse.InvalidRequestWithMessage("invalid content type")
+	}
+
+	if mediaType != "application/x-www-form-urlencoded" && mediaType != "multipart/form-data" {
+		return form, nil
+	}
+
+	body := r.Body().Bytes()
+	if limit := r.binding.options.MaxBodySize; limit > 0 && len(body) > limit {
+		return nil, PayloadTooLarge
+	}
+
+	if mediaType == "application/x-www-form-urlencoded" {
+		if form.Values, err = url.ParseQuery(string(body)); err != nil {
+			return nil, erresponse.InvalidRequestWithMessage("invalid form body")
+		}
+
+		return form, nil
+	}
+
+	if params["boundary"] == "" {
+		return nil, erresponse.InvalidRequestWithMessage("missing multipart boundary")
+	}
+
+	reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return form, nil
+		}
+
+		if err != nil {
+			return nil, erresponse.InvalidRequestWithMessage("invalid multipart body")
+		}
+
+		limit := r.binding.options.MaxFileSize
+		if part.FileName() == "" {
+			limit = r.binding.options.MaxBodySize
+		}
+
+		data, err := readPart(part, limit)
+		if err != nil {
+			return nil, err
+		}
+
+		if part.FileName() == "" {
+			form.Values.Add(part.FormName(), string(data))
+			continue
+		}
+
+		form.Files[part.FormName()] = append(form.Files[part.FormName()], &FormFile{
+			FieldName:   part.FormName(),
+			Filename:    part.FileName(),
+			ContentType: part.Header.Get(httpheadername.ContentType),
+			Header:      part.Header,
+			Size:        int64(len(data)),
+			data:        data,
+		})
+	}
+}
+
+func readPart(part *multipart.Part, limit int) ([]byte, error) {
+	var reader io.Reader = part
+	if limit > 0 {
+		reader = io.LimitReader(part, int64(limit)+1)
+	}
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, erresponse.InvalidRequestWithMessage("invalid multipart body")
+	}
+
+	if limit > 0 && len(data) > limit {
+		return nil, PayloadTooLarge
+	}
+
+	return data, nil
+}
@@ -0,0 +1,57 @@
+package gola
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"mime/multipart"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequest_FormValue(t *testing.T) {
+	req := NewRequest(events.ALBTargetGroupRequest{HTTPMethod: "POST", Body: "name=kk&tag=a&tag=b%20c",
+		MultiValueHeaders: map[string][]string{"Content-Type": {"application/x-www-form-urlencoded; charset=utf-8"}}}, nil)
+	assert.Equal(t, "kk", req.FormValue("name"))
+	assert.Equal(t, []string{"a", "b c"}, req.FormValues("tag"))
+	assert.Equal(t, "", req.FormValue("none"))
+
+	req = NewRequest(events.ALBTargetGroupRequest{HTTPMethod: "POST", Body: `{"name":"kk"}`,
+		MultiValueHeaders: map[string][]string{"Content-Type": {"application/json"}}}, nil)
+	assert.Equal(t, "", req.FormValue("name"))
+}
+
+func TestRequest_FormFile(t *testing.T) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	writer.WriteField("name", "kk")
+	file, _ := writer.CreateFormFile("avatar", "kk.png")
+	file.Write([]byte("png-data"))
+	writer.Close()
+
+	event := events.ALBTargetGroupRequest{HTTPMethod: "POST", IsBase64Encoded: true, Body: base64.StdEncoding.EncodeToString(body.Bytes()),
+		MultiValueHeaders: map[string][]string{"Content-Type": {writer.FormDataContentType()}}}
+	req := NewRequest(event, nil)
+	assert.Equal(t, "kk", req.FormValue("name"))
+	avatar, err := req.FormFile("avatar")
+	assert.Nil(t, err)
+	assert.Equal(t, "kk.png", avatar.Filename)
+	assert.Equal(t, "application/octet-stream", avatar.ContentType)
+	assert.EqualValues(t, 8, avatar.Size)
+	data, _ := io.ReadAll(avatar.Reader())
+	assert.Equal(t, "png-data", string(data))
+	_, err = req.FormFile("none")
+	assert.NotNil(t, err)
+
+	limited := newRequest(event, nil)
+	limited.binding.options.MaxFileSize = 4
+	_, err = limited.Form()
+	assert.Equal(t, PayloadTooLarge, err)
+	assert.Equal(t, "", limited.FormValue("name"))
+
+	event.MultiValueHeaders = map[string][]string{"Content-Type": {"multipart/form-data"}}
+	_, err = NewRequest(event, nil).Form()
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,33 @@
+package gola
+
+import (
+	"context"
+	"encoding/base64"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+func (g *GoLA) RegisterFunctionURL(ctx context.Context, request events.LambdaFunctionURLRequest) (events.LambdaFunctionURLResponse, error) {
+	resp, err := g.serve(ctx, newFunctionURLRequest(request, nil))
+	return *resp.BuildFunctionURL(), err
+}
+
+func NewFunctionURLRequest(req events.LambdaFunctionURLRequest, pathParameters map[string]string) Request {
+	return newFunctionURLRequest(req, pathParameters)
+}
+
+func newFunctionURLRequest(req events.LambdaFunctionURLRequest, pathParameters map[string]string) *request {
+	r := newPayloadV2Request(req.RequestContext.HTTP.Method, req.RawPath, req.RawQueryString, req.Cookies, req.Headers, req.Body, req.IsBase64Encoded, pathParameters)
+	r.event = req
+	return r
+}
+
+func (r *response) BuildFunctionURL() *events.LambdaFunctionURLResponse {
+	return &events.LambdaFunctionURLResponse{
+		StatusCode:      r.buildStatusCode(),
+		Headers:         joinHeaders(r.headers),
+		Body:            base64.StdEncoding.EncodeToString(r.body.Bytes()),
+		IsBase64Encoded: true,
+		Cookies:         r.setCookies(),
+	}
+}
@@ -0,0 +1,44 @@
+package gola
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	buf "github.com/kklab-com/goth-bytebuf"
+	"github.com/stretchr/testify/assert"
+)
+
+type FunctionURLTestHandler struct {
+	DefaultHttpHandler
+	t *testing.T
+}
+
+func (h *FunctionURLTestHandler) Index(ctx context.Context, request Request, response Response) (er error) {
+	assert.Equal(h.t, "a=1", request.GetHeader("Cookie"))
+	assert.Equal(h.t, "2", request.QueryValue("page"))
+	assert.IsType(h.t, events.LambdaFunctionURLRequest{}, request.Event())
+	response.SetCookie(http.Cookie{Name: "session", Value: "abc"})
+	response.SetBody(buf.NewByteBufString("INDEX"))
+	return
+}
+
+func TestGoLA_RegisterFunctionURL(t *testing.T) {
+	goLA := NewServe()
+	goLA.Route().SetEndpoint("/user/:user_id", &FunctionURLTestHandler{t: t})
+	response, err := goLA.RegisterFunctionURL(context.Background(), events.LambdaFunctionURLRequest{
+		RawPath:        "/user",
+		RawQueryString: "page=2",
+		Cookies:        []string{"a=1"},
+		RequestContext: events.LambdaFunctionURLRequestContext{
+			HTTP: events.LambdaFunctionURLRequestContextHTTPDescription{Method: "GET", Path: "/user"},
+		},
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("INDEX")), response.Body)
+	assert.Equal(t, []string{"session=abc"}, response.Cookies)
+}
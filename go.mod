@@ -8,13 +8,13 @@ require (
 	github.com/kklab-com/gone-httpstatus v0.0.0-20210329135420-5f09bea125ca
 	github.com/kklab-com/goth-bytebuf v1.0.1
 	github.com/kklab-com/goth-erresponse v1.0.0
+	github.com/kklab-com/goth-kkerror v0.0.0-20210329135318-f6c51d7cfc8c
 	github.com/kklab-com/goth-panic v1.1.0
 	github.com/stretchr/testify v1.7.2
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
-	github.com/kklab-com/goth-kkerror v0.0.0-20210329135318-f6c51d7cfc8c // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -2,8 +2,11 @@ package gola
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"reflect"
+	"strings"
 
 	"github.com/aws/aws-lambda-go/events"
 	httpheadername "github.com/kklab-com/gone-httpheadername"
@@ -13,19 +16,30 @@ import (
 )
 
 type GoLA struct {
-	route                                                            *Route
-	ctxInjectMap                                                     map[any]any
-	BeginHandler, NotFoundHandler, ServerErrorHandler, FinishHandler Handler
+	route                                                                                     *Route
+	ctxInjectMap                                                                              map[any]any
+	middlewares                                                                               []Middleware
+	errorMappers                                                                              []ErrorMapper
+	BeginHandler, NotFoundHandler, MethodNotAllowedHandler, ServerErrorHandler, FinishHandler Handler
+	ErrorRenderer                                                                             ErrorRenderer
+	Codec                                                                                     Codec
+	PathPolicy                                                                                PathPolicy
+	RedirectHosts                                                                             []string
+	BindOptions                                                                               BindOptions
+	PanicHook                                                                                 func(ctx context.Context, request Request, err *ErrorResponseImpl)
+	ErrorHook                                                                                 func(ctx context.Context, request Request, err error)
 }
 
 func NewServe() *GoLA {
 	return &GoLA{
-		route:              NewRoute(),
-		ctxInjectMap:       map[any]any{},
-		BeginHandler:       &DefaultEmptyHandler{},
-		NotFoundHandler:    &DefaultNotFoundHandler{},
-		ServerErrorHandler: &DefaultServerErrorHandler{},
-		FinishHandler:      &DefaultEmptyHandler{},
+		route:                   NewRoute(),
+		PathPolicy:              PathPolicy{Clean: true},
+		ctxInjectMap:            map[any]any{},
+		BeginHandler:            &DefaultEmptyHandler{},
+		NotFoundHandler:         &DefaultNotFoundHandler{},
+		MethodNotAllowedHandler: &DefaultMethodNotAllowedHandler{},
+		ServerErrorHandler:      &DefaultServerErrorHandler{},
+		FinishHandler:           &DefaultEmptyHandler{},
 	}
 }
 
@@ -48,6 +62,9 @@ func (g *GoLA) Context(key any) any {
 
 var NotImplemented = erresponse.NotImplemented
 
+// ErrHandled is returned by an endpoint handler which has completed response, the handlers after it are skipped.
+var ErrHandled = errors.New("gola: handled")
+
 const (
 	CtxGoLA             = "gola"
 	CtxGoLAParams       = "gola-params"
@@ -55,52 +72,216 @@ const (
 	CtxGoLANodeLast     = "gola-node-last"
 	CtxGoLAHandler      = "gola-handler"
 	CtxGoLAHandlerError = "gola-handler-error"
+	CtxGoLAPanic        = "gola-panic"
+	CtxGoLAInput        = "gola-input"
 )
 
 func (g *GoLA) Register(ctx context.Context, request events.ALBTargetGroupRequest) (events.ALBTargetGroupResponse, error) {
+	req := newRequest(request, nil)
+	resp, err := g.serve(ctx, req)
+	if req.singleValue {
+		return *resp.BuildSingleValue(), err
+	}
+
+	return *resp.Build(), err
+}
+
+func (g *GoLA) serve(ctx context.Context, req *request) (Response, error) {
 	ctx = context.WithValue(ctx, CtxGoLA, g)
 	ctx = context.WithValue(ctx, CtxGoLAParams, map[string]any{})
-	node, parameters, isLast := g.route.RouteNode(request.Path)
-	req := NewRequest(request, parameters)
-	resp := NewResponse()
-	for k, v := range g.ctxInjectMap {
-		ctx = context.WithValue(ctx, k, v)
+	path := req.Path()
+	if g.PathPolicy.Clean {
+		path = cleanPath(path)
 	}
 
-	if err := g.BeginHandler.Run(ctx, req, resp); err != nil {
-		panic(err)
+	node, parameters, isLast := g.route.routeNode(path, g.PathPolicy.CaseInsensitive)
+	if node != nil && g.PathPolicy.TrailingSlash == TrailingSlashStrict && !node.servesSlash(isLast, hasTrailingSlash(req.Path())) {
+		node, parameters, isLast = nil, nil, false
 	}
 
-	var lErr error
-	if node == nil {
-		lErr = g.NotFoundHandler.Run(ctx, req, resp)
-	} else {
+	req.pathParameters = parameters
+	req.binding = g.binding()
+	resp := newResponse(req.binding.codec)
+	resp.request, resp.redirectHosts = req, g.RedirectHosts
+	for k, v := range g.ctxInjectMap {
+		ctx = context.WithValue(ctx, k, v)
+	}
+
+	if node != nil {
 		ctx = context.WithValue(ctx, CtxGoLANode, node)
 		ctx = context.WithValue(ctx, CtxGoLANodeLast, isLast)
-		for _, handler := range node.Handlers() {
-			ctx = context.WithValue(ctx, CtxGoLAHandler, handler)
-			if err := handler.Run(ctx, req, resp); err != nil {
-				ctx = context.WithValue(ctx, CtxGoLAHandlerError, err)
-				if resp.StatusCode() != 0 {
-					lErr = err
-				} else {
-					if v, ok := err.(erresponse.ErrorResponse); ok {
-						wrapErrorResponse(v, resp)
-					} else {
-						lErr = g.ServerErrorHandler.Run(ctx, req, resp)
-					}
+	}
+
+	var dispatch Handler = HandlerFunc(func(ctx context.Context, request Request, response Response) error {
+		if node == nil {
+			return g.NotFoundHandler.Run(ctx, request, response)
+		}
+
+		if target, ok := g.PathPolicy.redirectPath(request, node, isLast, path); ok {
+			redirectPermanently(request, response, target)
+			return nil
+		}
+
+		handlers, middlewares, allow := nodeHandlers(node, request.Method())
+		if allow != nil {
+			var notAllowed Handler = HandlerFunc(func(ctx context.Context, request Request, response Response) error {
+				response.SetHeader(httpheadername.Allow, strings.Join(allow, ", "))
+				return g.MethodNotAllowedHandler.Run(ctx, request, response)
+			})
+
+			if !IsPreflight(request) {
+				return notAllowed.Run(ctx, request, response)
+			}
+
+			handlers, middlewares := preflightHandlers(node, request)
+			return g.handleError(ctx, request, response, Chain(g.runHandlers(append(handlers, notAllowed)), middlewares...).Run(ctx, request, response))
+		}
+
+		return g.handleError(ctx, request, response, Chain(g.runHandlers(handlers), middlewares...).Run(ctx, request, response))
+	})
+
+	dispatch = Chain(dispatch, g.middlewares...)
+	lErr := Chain(HandlerFunc(func(ctx context.Context, request Request, response Response) error {
+		return g.handleError(ctx, request, response, dispatch.Run(ctx, request, response))
+	}), g.recovery, g.beginFinish, g.recovery).Run(ctx, req, resp)
+
+	if lErr != nil && g.ErrorHook != nil {
+		g.ErrorHook(ctx, req, lErr)
+	}
+
+	return resp, lErr
+}
+
+// recovery converts a panic into ErrorResponseImpl and renders it by ServerErrorHandler,
+// the caught panic is reported to PanicHook and put into context as CtxGoLAPanic.
+func (g *GoLA) recovery(next Handler) Handler {
+	return HandlerFunc(func(ctx context.Context, request Request, response Response) (er error) {
+		defer func() {
+			if r := recover(); r != nil {
+				erErr := NewPanicErrorResponse(r)
+				ctx = context.WithValue(ctx, CtxGoLAHandlerError, erErr)
+				ctx = context.WithValue(ctx, CtxGoLAPanic, erErr.Caught)
+				if g.PanicHook != nil {
+					g.PanicHook(ctx, request, erErr)
 				}
 
-				break
+				er = g.renderPanic(ctx, request, response, erErr)
 			}
+		}()
+
+		return next.Run(ctx, request, response)
+	})
+}
+
+func (g *GoLA) renderPanic(ctx context.Context, request Request, response Response, erErr *ErrorResponseImpl) (er error) {
+	defer func() {
+		if r := recover(); r != nil {
+			wrapErrorResponse(erErr, response)
+			er = nil
 		}
+	}()
+
+	return g.runServerError(ctx, request, response, erErr)
+}
+
+func (g *GoLA) Use(middlewares ...Middleware) *GoLA {
+	g.middlewares = append(g.middlewares, middlewares...)
+	return g
+}
+
+func (g *GoLA) beginFinish(next Handler) Handler {
+	return HandlerFunc(func(ctx context.Context, request Request, response Response) error {
+		if err := g.BeginHandler.Run(ctx, request, response); err != nil {
+			panic(err)
+		}
+
+		lErr := next.Run(ctx, request, response)
+		if err := g.FinishHandler.Run(ctx, request, response); err != nil {
+			panic(err)
+		}
+
+		return lErr
+	})
+}
+
+func (g *GoLA) runHandlers(handlers []Handler) Handler {
+	return HandlerFunc(func(ctx context.Context, request Request, response Response) error {
+		for _, handler := range handlers {
+			ctx = context.WithValue(ctx, CtxGoLAHandler, handler)
+			if err := handler.Run(ctx, request, response); err != nil {
+				return g.handleError(ctx, request, response, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (g *GoLA) handleError(ctx context.Context, request Request, response Response, err error) error {
+	if err == nil || errors.Is(err, ErrHandled) {
+		return nil
+	}
+
+	ctx = context.WithValue(ctx, CtxGoLAHandlerError, err)
+	if response.StatusCode() != 0 {
+		return err
 	}
 
-	if err := g.FinishHandler.Run(ctx, req, resp); err != nil {
-		panic(err)
+	if v, ok := g.mapError(err); ok {
+		g.renderError(ctx, request, response, v)
+		return nil
+	}
+
+	return g.runServerError(ctx, request, response, err)
+}
+
+func (g *GoLA) runServerError(ctx context.Context, request Request, response Response, err error) error {
+	if handler, ok := g.ServerErrorHandler.(ErrorHandler); ok {
+		return handler.RunError(ctx, request, response, err)
+	}
+
+	return g.ServerErrorHandler.Run(ctx, request, response)
+}
+
+// ErrorMapper converts err into the ErrorResponse rendered to client, ok is false when err is not recognized.
+type ErrorMapper func(err error) (resp erresponse.ErrorResponse, ok bool)
+
+// MapError registers mappers, they are tried in order before falling back to the ErrorResponse in the err chain.
+func (g *GoLA) MapError(mappers ...ErrorMapper) *GoLA {
+	g.errorMappers = append(g.errorMappers, mappers...)
+	return g
+}
+
+func (g *GoLA) mapError(err error) (erresponse.ErrorResponse, bool) {
+	for _, mapper := range g.errorMappers {
+		if v, ok := mapper(err); ok && v != nil {
+			return v, true
+		}
+	}
+
+	var v erresponse.ErrorResponse
+	if errors.As(err, &v) {
+		return v, true
+	}
+
+	return nil, false
+}
+
+func ErrorIs(target error, resp erresponse.ErrorResponse) ErrorMapper {
+	return func(err error) (erresponse.ErrorResponse, bool) {
+		return resp, errors.Is(err, target)
 	}
+}
 
-	return *resp.Build(), lErr
+func ErrorAs[T error](convert func(err T) erresponse.ErrorResponse) ErrorMapper {
+	return func(err error) (erresponse.ErrorResponse, bool) {
+		var target T
+		if errors.As(err, &target) {
+			return convert(target), true
+		}
+
+		return nil, false
+	}
 }
 
 func wrapErrorResponse(err erresponse.ErrorResponse, resp Response) {
@@ -109,7 +290,36 @@ func wrapErrorResponse(err erresponse.ErrorResponse, resp Response) {
 		JSONResponse(buf.NewByteBufString(err.Error()))
 }
 
+// ErrorRenderer writes ErrorResponse into response, GoLA renders erresponse JSON when it is nil.
+type ErrorRenderer interface {
+	RenderError(ctx context.Context, request Request, response Response, err erresponse.ErrorResponse)
+}
+
+func (g *GoLA) renderError(ctx context.Context, request Request, response Response, err erresponse.ErrorResponse) {
+	if g.ErrorRenderer != nil {
+		g.ErrorRenderer.RenderError(ctx, request, response, err)
+		return
+	}
+
+	wrapErrorResponse(err, response)
+}
+
+// RenderError renders err by the ErrorRenderer of GoLA serving ctx.
+func RenderError(ctx context.Context, request Request, response Response, err erresponse.ErrorResponse) {
+	if g, ok := ctx.Value(CtxGoLA).(*GoLA); ok {
+		g.renderError(ctx, request, response, err)
+		return
+	}
+
+	wrapErrorResponse(err, response)
+}
+
+// CORSHelper reflects request origin, headers and methods, responses already handled by CORS are left untouched.
 func CORSHelper(request Request, response Response) {
+	if corsApplied(response) {
+		return
+	}
+
 	if v := request.GetHeader(httpheadername.Origin); v == "null" {
 		response.SetHeader(httpheadername.AccessControlAllowOrigin, "*")
 	} else {
@@ -125,6 +335,22 @@ func CORSHelper(request Request, response Response) {
 	}
 }
 
+func corsApplied(response Response) bool {
+	if response.GetHeader(httpheadername.AccessControlAllowOrigin) != "" {
+		return true
+	}
+
+	for _, value := range response.GetHeaders(httpheadername.Vary) {
+		for _, v := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(v), "Origin") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 type ErrorResponseImpl struct {
 	erresponse.ErrorResponse
 	Caught *kkpanic.CaughtImpl `json:"caught,omitempty"`
@@ -134,10 +360,49 @@ func (e *ErrorResponseImpl) String() string {
 	return e.Caught.String()
 }
 
+func NewPanicErrorResponse(r any) *ErrorResponseImpl {
+	erErr := &ErrorResponseImpl{
+		ErrorResponse: erresponse.ServerErrorPanic,
+	}
+
+	switch er := r.(type) {
+	case *kkpanic.CaughtImpl:
+		erErr.Caught = er
+	default:
+		erErr.Caught = kkpanic.Convert(er)
+	}
+
+	return erErr
+}
+
 type Handler interface {
 	Run(ctx context.Context, request Request, response Response) (er error)
 }
 
+// ErrorHandler is an optional interface of ServerErrorHandler to receive the error it renders.
+type ErrorHandler interface {
+	Handler
+	RunError(ctx context.Context, request Request, response Response, err error) (er error)
+}
+
+type HandlerFunc func(ctx context.Context, request Request, response Response) (er error)
+
+func (f HandlerFunc) Run(ctx context.Context, request Request, response Response) (er error) {
+	return f(ctx, request, response)
+}
+
+// Middleware wraps the downstream Handler, it decides when, or whether, to call next.
+type Middleware func(next Handler) Handler
+
+// Chain wraps handler by middlewares, the first middleware is the outermost one.
+func Chain(handler Handler, middlewares ...Middleware) Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+
+	return handler
+}
+
 type DefaultHandler struct {
 }
 
@@ -161,6 +426,11 @@ func (d *DefaultHandler) SetParam(ctx context.Context, key string, value any) {
 	ctx.Value(CtxGoLAParams).(map[string]any)[key] = value
 }
 
+// Input returns the struct bound for InputHandler, nil when there is none.
+func (d *DefaultHandler) Input(ctx context.Context) any {
+	return ctx.Value(CtxGoLAInput)
+}
+
 func (d *DefaultHandler) Run(ctx context.Context, request Request, response Response) (er error) {
 	CORSHelper(request, response)
 	response.SetContentType("text/plain")
@@ -183,6 +453,28 @@ type HttpHandler interface {
 	ErrorCaught(ctx context.Context, request Request, response Response, err erresponse.ErrorResponse)
 }
 
+// InputHandler is implemented by HttpHandler to receive validated input by DefaultHandler.Input,
+// NewInput returns a new struct pointer to Bind for method, nil skips binding.
+// Inputs are checked when the handler is registered, invalid `validate` tags panic there.
+type InputHandler interface {
+	NewInput(method string) any
+}
+
+var inputMethods = []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+	http.MethodDelete, http.MethodOptions, http.MethodTrace, http.MethodConnect}
+
+func checkInputs(handlers []Handler) {
+	for _, handler := range handlers {
+		if inputHandler, ok := handler.(InputHandler); ok {
+			for _, method := range inputMethods {
+				if err := checkTags(reflect.TypeOf(inputHandler.NewInput(method))); err != nil {
+					panic(err)
+				}
+			}
+		}
+	}
+}
+
 type DefaultHttpHandler struct {
 	DefaultHandler
 }
@@ -195,22 +487,12 @@ func (h *DefaultHttpHandler) Run(ctx context.Context, request Request, response
 		return
 	}
 
+	// ErrorCaught is notified and the panic goes on to GoLA, which renders it and calls PanicHook.
 	defer func(handler HttpHandler, ctx context.Context, request Request, response Response) {
 		if r := recover(); r != nil {
-			erErr := &ErrorResponseImpl{
-				ErrorResponse: erresponse.ServerErrorPanic,
-			}
-
-			switch er := r.(type) {
-			case *kkpanic.CaughtImpl:
-				erErr.Caught = er
-			default:
-				erErr.Caught = kkpanic.Convert(er)
-			}
-
-			ctx = context.WithValue(ctx, CtxGoLAHandlerError, erErr)
-			wrapErrorResponse(erErr, response)
-			handler.ErrorCaught(ctx, request, response, erErr)
+			erErr := NewPanicErrorResponse(r)
+			handler.ErrorCaught(context.WithValue(ctx, CtxGoLAHandlerError, erErr), request, response, erErr)
+			panic(erErr.Caught)
 		}
 
 	}(httpHandler, ctx, request, response)
@@ -219,6 +501,16 @@ func (h *DefaultHttpHandler) Run(ctx context.Context, request Request, response
 		return err
 	}
 
+	if inputHandler, ok := handler.(InputHandler); ok {
+		if input := inputHandler.NewInput(request.Method()); input != nil {
+			if err = request.Bind(input); err != nil {
+				return err
+			}
+
+			ctx = context.WithValue(ctx, CtxGoLAInput, input)
+		}
+	}
+
 	switch {
 	case request.Method() == http.MethodGet:
 		if ctx.Value(CtxGoLANodeLast).(bool) {
@@ -332,10 +624,16 @@ type DefaultNotFoundHandler struct {
 
 func (d *DefaultNotFoundHandler) Run(ctx context.Context, request Request, response Response) (er error) {
 	CORSHelper(request, response)
-	response.
-		SetStatusCode(erresponse.NotFound.ErrorStatusCode()).
-		JSONResponse(buf.NewByteBufString(erresponse.NotFound.Error()))
+	RenderError(ctx, request, response, erresponse.NotFound)
+	return nil
+}
+
+type DefaultMethodNotAllowedHandler struct {
+}
 
+func (d *DefaultMethodNotAllowedHandler) Run(ctx context.Context, request Request, response Response) (er error) {
+	CORSHelper(request, response)
+	RenderError(ctx, request, response, erresponse.MethodNotAllowed)
 	return nil
 }
 
@@ -344,13 +642,14 @@ type DefaultServerErrorHandler struct {
 
 func (d *DefaultServerErrorHandler) Run(ctx context.Context, request Request, response Response) (er error) {
 	CORSHelper(request, response)
-	response.
-		SetStatusCode(erresponse.ServerError.ErrorStatusCode()).
-		JSONResponse(buf.NewByteBufString(erresponse.ServerError.Error()))
-
+	RenderError(ctx, request, response, erresponse.ServerError)
 	return nil
 }
 
+func (d *DefaultServerErrorHandler) RunError(ctx context.Context, request Request, response Response, err error) (er error) {
+	return d.Run(ctx, request, response)
+}
+
 type DefaultCORSHandler struct {
 }
 
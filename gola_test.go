@@ -3,6 +3,10 @@ package gola
 import (
 	"context"
 	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/aws/aws-lambda-go/events"
@@ -152,8 +156,268 @@ func TestGoLA_Register(t *testing.T) {
 	assert.Equal(t, 200, response.StatusCode)
 	assert.Nil(t, err)
 
+	// /auth/abc/cde has no parameter declared, so the extra segment is not matched.
 	response, err = goLA.Register(context.Background(), events.ALBTargetGroupRequest{Path: "/auth/abc/cde/123", HTTPMethod: "GET", MultiValueHeaders: map[string][]string{"access-control-request-headers": {"content-type"}, "access-control-request-method": {"POST"}}})
+	assert.Equal(t, 404, response.StatusCode)
+	assert.Nil(t, err)
+
+}
+
+type GOLATestSingleValueHandler struct {
+	DefaultHttpHandler
+	t *testing.T
+}
+
+func (h *GOLATestSingleValueHandler) Get(ctx context.Context, request Request, response Response) (er error) {
+	assert.Equal(h.t, "value", request.GetHeader("X-Test"))
+	assert.Equal(h.t, "2", request.QueryValue("page"))
+	response.SetHeader("X-Multi", "1").AddHeader("X-Multi", "2")
+	response.SetCookie(http.Cookie{Name: "a", Value: "1"})
+	response.SetBody(buf.NewByteBufString("GET"))
+	return
+}
+
+func TestGoLA_RegisterSingleValue(t *testing.T) {
+	goLA := NewServe()
+	goLA.Route().SetEndpoint("/user/:user_id", &GOLATestSingleValueHandler{t: t})
+	response, err := goLA.Register(context.Background(), events.ALBTargetGroupRequest{Path: "/user/123", HTTPMethod: "GET", Headers: map[string]string{"x-test": "value"}, QueryStringParameters: map[string]string{"page": "2"}})
+	assert.Nil(t, err)
 	assert.Equal(t, 200, response.StatusCode)
+	assert.Nil(t, response.MultiValueHeaders)
+	assert.Equal(t, "1, 2", response.Headers["X-Multi"])
+	assert.Equal(t, "a=1", response.Headers["Set-Cookie"])
+
+	response, err = goLA.Register(context.Background(), events.ALBTargetGroupRequest{Path: "/user/123", HTTPMethod: "GET", MultiValueHeaders: map[string][]string{"x-test": {"value"}}, MultiValueQueryStringParameters: map[string][]string{"page": {"2"}}})
+	assert.Nil(t, err)
+	assert.Empty(t, response.Headers)
+	assert.Equal(t, []string{"1", "2"}, response.MultiValueHeaders["X-Multi"])
+}
+
+func TestResponse_BuildSingleValueCookies(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		response := NewResponse()
+		response.AddHeader("Set-Cookie", "h=0")
+		response.SetCookie(http.Cookie{Name: "c", Value: "1"})
+		response.SetCookie(http.Cookie{Name: "a", Value: "1"})
+		response.SetCookie(http.Cookie{Name: "b", Value: "2"})
+		response.SetCookie(http.Cookie{Name: "c", Value: "3"})
+		response.Cookies()["d"] = []http.Cookie{{Name: "d", Value: "4"}}
+		assert.Equal(t, []string{"h=0", "c=3", "a=1", "b=2", "d=4"}, response.Build().MultiValueHeaders["Set-Cookie"])
+		assert.Equal(t, "d=4", response.BuildSingleValue().Headers["Set-Cookie"])
+		assert.Equal(t, []string{"h=0", "c=3", "a=1", "b=2", "d=4"}, response.BuildFunctionURL().Cookies)
+		assert.Empty(t, response.BuildFunctionURL().Headers["Set-Cookie"])
+	}
+}
+
+func GOLATestTraceMiddleware(name string) Middleware {
+	return func(next Handler) Handler {
+		return HandlerFunc(func(ctx context.Context, request Request, response Response) error {
+			response.AddHeader("Trace", name+"-in")
+			err := next.Run(ctx, request, response)
+			response.AddHeader("Trace", name+"-out")
+			return err
+		})
+	}
+}
+
+func TestGoLA_Use(t *testing.T) {
+	goLA := NewServe()
+	goLA.BeginHandler = HandlerFunc(func(ctx context.Context, request Request, response Response) error {
+		response.AddHeader("Trace", "begin")
+		return nil
+	})
+
+	goLA.FinishHandler = HandlerFunc(func(ctx context.Context, request Request, response Response) error {
+		response.AddHeader("Trace", "finish")
+		return nil
+	})
+
+	goLA.Use(GOLATestTraceMiddleware("global"))
+	route := goLA.Route()
+	group := route.Group("/group").Use(GOLATestTraceMiddleware("group"))
+	group.With(GOLATestTraceMiddleware("endpoint")).SetEndpoint("/user/:user_id", HandlerFunc(func(ctx context.Context, request Request, response Response) error {
+		response.AddHeader("Trace", "handler")
+		return nil
+	}))
+
+	group.Get("/bad", HandlerFunc(func(ctx context.Context, request Request, response Response) error {
+		return erresponse.InvalidRequest
+	}))
+
+	route.With(func(next Handler) Handler {
+		return HandlerFunc(func(ctx context.Context, request Request, response Response) error {
+			response.SetStatusCode(http.StatusAccepted)
+			return nil
+		})
+	}).SetEndpoint("/halt", HandlerFunc(func(ctx context.Context, request Request, response Response) error {
+		t.Fail()
+		return nil
+	}))
+
+	response, err := goLA.Register(context.Background(), events.ALBTargetGroupRequest{Path: "/group/user/123", HTTPMethod: "GET"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"begin", "global-in", "group-in", "endpoint-in", "handler", "endpoint-out", "group-out", "global-out", "finish"}, response.MultiValueHeaders["Trace"])
+
+	response, err = goLA.Register(context.Background(), events.ALBTargetGroupRequest{Path: "/group/bad", HTTPMethod: "GET"})
+	assert.Nil(t, err)
+	assert.Equal(t, 400, response.StatusCode)
+	assert.Equal(t, []string{"begin", "global-in", "group-in", "group-out", "global-out", "finish"}, response.MultiValueHeaders["Trace"])
+
+	response, err = goLA.Register(context.Background(), events.ALBTargetGroupRequest{Path: "/halt", HTTPMethod: "GET"})
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusAccepted, response.StatusCode)
+
+	response, err = goLA.Register(context.Background(), events.ALBTargetGroupRequest{Path: "/none", HTTPMethod: "GET"})
 	assert.Nil(t, err)
+	assert.Equal(t, 404, response.StatusCode)
+	assert.Equal(t, []string{"begin", "global-in", "global-out", "finish"}, response.MultiValueHeaders["Trace"])
+}
 
+func TestGoLA_RegisterPanic(t *testing.T) {
+	goLA := NewServe()
+	var caught []*ErrorResponseImpl
+	finished := 0
+	goLA.PanicHook = func(ctx context.Context, request Request, err *ErrorResponseImpl) {
+		assert.NotNil(t, ctx.Value(CtxGoLAPanic))
+		assert.Equal(t, err, ctx.Value(CtxGoLAHandlerError))
+		caught = append(caught, err)
+	}
+
+	goLA.FinishHandler = HandlerFunc(func(ctx context.Context, request Request, response Response) error {
+		finished++
+		return nil
+	})
+
+	goLA.Route().SetEndpoint("/panic", HandlerFunc(func(ctx context.Context, request Request, response Response) error {
+		panic("plain panic")
+	}))
+
+	response, err := goLA.Register(context.Background(), events.ALBTargetGroupRequest{Path: "/panic", HTTPMethod: "GET"})
+	assert.Nil(t, err)
+	assert.Equal(t, 500, response.StatusCode)
+	assert.Equal(t, 1, len(caught))
+	assert.Equal(t, "plain panic", caught[0].Caught.Message)
+	assert.NotEmpty(t, caught[0].Caught.CallStack)
+	assert.Equal(t, 1, finished)
+
+	goLA.Route().SetEndpoint("/http-panic", &GOLATestRegisterPanicHandler{t: t})
+	response, err = goLA.Register(context.Background(), events.ALBTargetGroupRequest{Path: "/http-panic", HTTPMethod: "GET"})
+	assert.Nil(t, err)
+	assert.Equal(t, 500, response.StatusCode)
+	assert.Equal(t, 2, len(caught))
+	assert.Equal(t, "panic", caught[1].Caught.Message)
+	assert.Equal(t, 2, finished)
+
+	goLA.NotFoundHandler = HandlerFunc(func(ctx context.Context, request Request, response Response) error {
+		panic("not found panic")
+	})
+
+	response, err = goLA.Register(context.Background(), events.ALBTargetGroupRequest{Path: "/none", HTTPMethod: "GET"})
+	assert.Nil(t, err)
+	assert.Equal(t, 500, response.StatusCode)
+	assert.Equal(t, 3, len(caught))
+	assert.Equal(t, 3, finished)
+
+	goLA.BeginHandler = HandlerFunc(func(ctx context.Context, request Request, response Response) error {
+		return erresponse.ServerError
+	})
+
+	goLA.ServerErrorHandler = HandlerFunc(func(ctx context.Context, request Request, response Response) error {
+		panic("server error panic")
+	})
+
+	response, err = goLA.Register(context.Background(), events.ALBTargetGroupRequest{Path: "/panic", HTTPMethod: "GET"})
+	assert.Nil(t, err)
+	assert.Equal(t, 500, response.StatusCode)
+	assert.Equal(t, 4, len(caught))
+	assert.Equal(t, 3, finished)
+}
+
+var GOLATestErrNoQuota = errors.New("no quota")
+
+type GOLATestValidationError struct {
+	Field string
+}
+
+func (e *GOLATestValidationError) Error() string {
+	return e.Field + " is invalid"
+}
+
+type GOLATestServerErrorHandler struct {
+	DefaultServerErrorHandler
+	err error
+}
+
+func (h *GOLATestServerErrorHandler) RunError(ctx context.Context, request Request, response Response, err error) (er error) {
+	h.err = err
+	return h.Run(ctx, request, response)
+}
+
+func TestGoLA_MapError(t *testing.T) {
+	goLA := NewServe()
+	serverErrorHandler := &GOLATestServerErrorHandler{}
+	goLA.ServerErrorHandler = serverErrorHandler
+	goLA.MapError(
+		ErrorIs(GOLATestErrNoQuota, erresponse.SlowDownTooFast),
+		ErrorAs(func(err *GOLATestValidationError) erresponse.ErrorResponse {
+			return erresponse.InvalidRequestInvalidDataOfName(err.Field)
+		}),
+	)
+
+	var handlerErr error
+	goLA.Route().SetEndpoint("/error", HandlerFunc(func(ctx context.Context, request Request, response Response) error {
+		return handlerErr
+	}))
+
+	handlerErr = fmt.Errorf("charge: %w", GOLATestErrNoQuota)
+	response, err := goLA.Register(context.Background(), events.ALBTargetGroupRequest{Path: "/error", HTTPMethod: "GET"})
+	assert.Nil(t, err)
+	assert.Equal(t, erresponse.SlowDownTooFast.ErrorStatusCode(), response.StatusCode)
+
+	handlerErr = fmt.Errorf("bind: %w", &GOLATestValidationError{Field: "name"})
+	response, err = goLA.Register(context.Background(), events.ALBTargetGroupRequest{Path: "/error", HTTPMethod: "GET"})
+	assert.Nil(t, err)
+	assert.Equal(t, 400, response.StatusCode)
+	body, _ := base64.StdEncoding.DecodeString(response.Body)
+	assert.Contains(t, string(body), "name has invalid data")
+
+	handlerErr = fmt.Errorf("load: %w", erresponse.NotFound)
+	response, err = goLA.Register(context.Background(), events.ALBTargetGroupRequest{Path: "/error", HTTPMethod: "GET"})
+	assert.Nil(t, err)
+	assert.Equal(t, 404, response.StatusCode)
+	assert.Nil(t, serverErrorHandler.err)
+
+	handlerErr = errors.New("unknown")
+	response, err = goLA.Register(context.Background(), events.ALBTargetGroupRequest{Path: "/error", HTTPMethod: "GET"})
+	assert.Nil(t, err)
+	assert.Equal(t, 500, response.StatusCode)
+	assert.Equal(t, handlerErr, serverErrorHandler.err)
+}
+
+func TestGoLA_ErrorHook(t *testing.T) {
+	goLA := NewServe()
+	var reported []error
+	goLA.ErrorHook = func(ctx context.Context, request Request, err error) {
+		assert.Equal(t, "/late", request.Path())
+		reported = append(reported, err)
+	}
+
+	goLA.Route().SetEndpoint("/late", HandlerFunc(func(ctx context.Context, request Request, response Response) error {
+		response.SetStatusCode(202)
+		return GOLATestErrNoQuota
+	}))
+
+	response, err := goLA.Register(context.Background(), events.ALBTargetGroupRequest{Path: "/late", HTTPMethod: "GET"})
+	assert.Equal(t, GOLATestErrNoQuota, err)
+	assert.Equal(t, 202, response.StatusCode)
+	assert.Equal(t, []error{GOLATestErrNoQuota}, reported)
+
+	recorder := httptest.NewRecorder()
+	goLA.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/late", nil))
+	assert.Equal(t, 202, recorder.Code)
+	assert.Equal(t, 2, len(reported))
+
+	_, err = goLA.Register(context.Background(), events.ALBTargetGroupRequest{Path: "/none", HTTPMethod: "GET"})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(reported))
 }
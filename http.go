@@ -0,0 +1,152 @@
+package gola
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	httpheadername "github.com/kklab-com/gone-httpheadername"
+	buf "github.com/kklab-com/goth-bytebuf"
+)
+
+func (g *GoLA) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// the response is written as handler left it, error returned by Register is reported to ErrorHook
+	response, _ := g.Register(r.Context(), NewALBTargetGroupRequest(r))
+	for key, values := range response.MultiValueHeaders {
+		for _, value := range values {
+			w.Header().Add(key, value)
+		}
+	}
+
+	body := []byte(response.Body)
+	if response.IsBase64Encoded {
+		if decoded, err := base64.StdEncoding.DecodeString(response.Body); err == nil {
+			body = decoded
+		}
+	}
+
+	w.WriteHeader(response.StatusCode)
+	w.Write(body)
+}
+
+func NewALBTargetGroupRequest(r *http.Request) events.ALBTargetGroupRequest {
+	headers := r.Header.Clone()
+	if r.Host != "" {
+		headers.Set(httpheadername.Host, r.Host)
+	}
+
+	event := events.ALBTargetGroupRequest{
+		HTTPMethod:                      r.Method,
+		Path:                            r.URL.EscapedPath(),
+		MultiValueQueryStringParameters: rawQueryValues(r.URL.RawQuery),
+		MultiValueHeaders:               headers,
+	}
+
+	if r.Body != nil {
+		if body, err := io.ReadAll(r.Body); err == nil && len(body) > 0 {
+			event.Body = base64.StdEncoding.EncodeToString(body)
+			event.IsBase64Encoded = true
+		}
+	}
+
+	return event
+}
+
+// rawQueryValues keeps keys and values percent-encoded, the same as ALB does.
+func rawQueryValues(rawQuery string) map[string][]string {
+	values := map[string][]string{}
+	for _, pair := range strings.Split(rawQuery, "&") {
+		if pair == "" {
+			continue
+		}
+
+		key, value, _ := strings.Cut(pair, "=")
+		values[key] = append(values[key], value)
+	}
+
+	return values
+}
+
+type StdHttpHandler struct {
+	Handler http.Handler
+}
+
+func WrapHttpHandler(handler http.Handler) *StdHttpHandler {
+	return &StdHttpHandler{Handler: handler}
+}
+
+func (h *StdHttpHandler) Run(ctx context.Context, request Request, response Response) (er error) {
+	req, err := NewHttpRequest(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	writer := &responseWriter{response: response}
+	h.Handler.ServeHTTP(writer, req)
+	if !writer.wroteHeader {
+		writer.WriteHeader(http.StatusOK)
+	}
+
+	return nil
+}
+
+func NewHttpRequest(ctx context.Context, request Request) (*http.Request, error) {
+	target := &url.URL{
+		Path:     request.Path(),
+		RawQuery: request.RawQuery(),
+	}
+
+	if unescaped, err := url.PathUnescape(target.Path); err == nil {
+		target.RawPath = target.Path
+		target.Path = unescaped
+	}
+
+	req, err := http.NewRequestWithContext(ctx, request.Method(), target.String(), bytes.NewReader(request.Body().Bytes()))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header = request.Header().Clone()
+	req.Host = request.GetHeader(httpheadername.Host)
+	req.RequestURI = target.RequestURI()
+	if forwardedFor := request.GetHeader(httpheadername.XForwardedFor); forwardedFor != "" {
+		req.RemoteAddr = strings.TrimSpace(strings.Split(forwardedFor, ",")[0])
+	}
+
+	return req, nil
+}
+
+type responseWriter struct {
+	response    Response
+	body        buf.ByteBuf
+	wroteHeader bool
+}
+
+func (w *responseWriter) Header() http.Header {
+	return w.response.Header()
+}
+
+func (w *responseWriter) WriteHeader(statusCode int) {
+	if w.wroteHeader {
+		return
+	}
+
+	w.wroteHeader = true
+	w.response.SetStatusCode(statusCode)
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	if w.body == nil {
+		w.body = buf.EmptyByteBuf()
+		w.response.SetBody(w.body)
+	}
+
+	w.body.WriteBytes(p)
+	return len(p), nil
+}
@@ -0,0 +1,85 @@
+package gola
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	buf "github.com/kklab-com/goth-bytebuf"
+	"github.com/stretchr/testify/assert"
+)
+
+type HTTPTestHandler struct {
+	DefaultHttpHandler
+	t *testing.T
+}
+
+func (h *HTTPTestHandler) Post(ctx context.Context, request Request, response Response) (er error) {
+	assert.Equal(h.t, "123", request.PathParameter("user_id"))
+	assert.Equal(h.t, "example.com", request.GetHeader("Host"))
+	assert.Equal(h.t, "text/plain", request.GetHeader("Content-Type"))
+	assert.Equal(h.t, []string{"1", "2"}, request.QueryValues("page"))
+	assert.Equal(h.t, "body", string(request.Body().Bytes()))
+	response.SetStatusCode(201).SetHeader("X-Test", "value")
+	response.SetCookie(http.Cookie{Name: "session", Value: "abc"})
+	response.SetBody(buf.NewByteBufString("POST"))
+	return
+}
+
+func TestGoLA_ServeHTTP(t *testing.T) {
+	goLA := NewServe()
+	goLA.Route().SetEndpoint("/user/:user_id", &HTTPTestHandler{t: t})
+	server := httptest.NewServer(goLA)
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/user/123?page=1&page=2", strings.NewReader("body"))
+	req.Host = "example.com"
+	req.Header.Set("Content-Type", "text/plain")
+	resp, err := http.DefaultClient.Do(req)
+	assert.Nil(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, 201, resp.StatusCode)
+	assert.Equal(t, "value", resp.Header.Get("X-Test"))
+	assert.Equal(t, "session=abc", resp.Header.Get("Set-Cookie"))
+	assert.Equal(t, "POST", string(body))
+
+	recorder := httptest.NewRecorder()
+	goLA.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/none", nil))
+	assert.Equal(t, 404, recorder.Code)
+}
+
+func TestStdHttpHandler_Run(t *testing.T) {
+	goLA := NewServe()
+	goLA.Route().SetEndpoint("/debug/*", WrapHttpHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, "/debug/vars", r.URL.Path)
+		assert.Equal(t, "1", r.URL.Query().Get("page"))
+		assert.Equal(t, "a b&c", r.URL.Query().Get("q"))
+		assert.Equal(t, "page=1&q=a%20b%26c", r.URL.RawQuery)
+		assert.Equal(t, "example.com", r.Host)
+		assert.Equal(t, "body", string(body))
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte("hello "))
+		w.Write([]byte("world"))
+	})))
+
+	response, err := goLA.Register(context.Background(), events.ALBTargetGroupRequest{
+		Path:                            "/debug/vars",
+		HTTPMethod:                      "PUT",
+		MultiValueHeaders:               map[string][]string{"host": {"example.com"}},
+		MultiValueQueryStringParameters: map[string][]string{"page": {"1"}, "q": {"a%20b%26c"}},
+		Body:                            "body",
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusAccepted, response.StatusCode)
+	assert.Equal(t, []string{"text/plain"}, response.MultiValueHeaders["Content-Type"])
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("hello world")), response.Body)
+}
@@ -3,18 +3,28 @@ package gola
 import (
 	"encoding/base64"
 	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-lambda-go/events"
 	httpheadername "github.com/kklab-com/gone-httpheadername"
-	httpstatus "github.com/kklab-com/gone-httpstatus"
 	buf "github.com/kklab-com/goth-bytebuf"
+	erresponse "github.com/kklab-com/goth-erresponse"
 )
 
+var uuidPattern = regexp.MustCompile("^(?:" + parameterTypes["uuid"] + ")$")
+
 type Request interface {
 	Request() *events.ALBTargetGroupRequest
 	Method() string
 	Path() string
 	PathParameter(name string) string
+	PathParameterInt(name string) (int, error)
+	PathParameterUUID(name string) (string, error)
 	TraceId() string
 	UserAgent() string
 	Header() http.Header
@@ -22,12 +32,41 @@ type Request interface {
 	GetHeaders(name string) []string
 	QueryValue(name string) string
 	QueryValues(name string) []string
+	Query() url.Values
+	RawQuery() string
+	QueryInt(name string) (int, error)
+	QueryBool(name string) (bool, error)
+	QueryTime(name string, layout string) (time.Time, error)
+	QueryCSV(name string) []string
+	BindQuery(v any) error
 	Body() buf.ByteBuf
+	Event() any
+	Stage() string
+	Authorizer() map[string]any
+	Claims() map[string]any
+	BindJSON(v any) error
+	Bind(v any) error
+	Form() (*Form, error)
+	FormValue(name string) string
+	FormValues(name string) []string
+	FormFile(name string) (*FormFile, error)
+	Cookie(name string) *http.Cookie
+	Cookies() []*http.Cookie
 }
 
 type request struct {
 	base           *events.ALBTargetGroupRequest
 	pathParameters map[string]string
+	query          url.Values
+	rawQuery       string
+	event          any
+	stage          string
+	authorizer     map[string]any
+	claims         map[string]any
+	singleValue    bool
+	binding        binding
+	form           *Form
+	formErr        error
 }
 
 func (r *request) Request() *events.ALBTargetGroupRequest {
@@ -35,15 +74,76 @@ func (r *request) Request() *events.ALBTargetGroupRequest {
 }
 
 func NewRequest(req events.ALBTargetGroupRequest, pathParameters map[string]string) Request {
+	return newRequest(req, pathParameters)
+}
+
+func newRequest(req events.ALBTargetGroupRequest, pathParameters map[string]string) *request {
+	event := req
+	singleValue := len(req.MultiValueHeaders) == 0 && len(req.MultiValueQueryStringParameters) == 0 &&
+		(len(req.Headers) > 0 || len(req.QueryStringParameters) > 0)
+
 	mHeaders := http.Header{}
-	for key, values := range req.MultiValueHeaders {
+	for key, values := range multiValues(req.Headers, req.MultiValueHeaders) {
 		for _, val := range values {
 			mHeaders.Add(key, val)
 		}
 	}
 
 	req.MultiValueHeaders = mHeaders
-	return &request{base: &req, pathParameters: pathParameters}
+	req.MultiValueQueryStringParameters = multiValues(req.QueryStringParameters, req.MultiValueQueryStringParameters)
+	return &request{base: &req, pathParameters: pathParameters, query: decodeQuery(req.MultiValueQueryStringParameters),
+		rawQuery: joinQuery(req.MultiValueQueryStringParameters), event: event, singleValue: singleValue}
+}
+
+// joinQuery rebuilds query string of ALB without encoding again, keys are sorted as ALB does not keep the order.
+func joinQuery(raw map[string][]string) string {
+	keys := make([]string, 0, len(raw))
+	for key := range raw {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	var pairs []string
+	for _, key := range keys {
+		for _, value := range raw[key] {
+			pairs = append(pairs, key+"="+value)
+		}
+	}
+
+	return strings.Join(pairs, "&")
+}
+
+// decodeQuery unescapes keys and values which ALB passes percent-encoded, malformed escapes are kept as is.
+func decodeQuery(raw map[string][]string) url.Values {
+	query := url.Values{}
+	for key, values := range raw {
+		if k, err := url.QueryUnescape(key); err == nil {
+			key = k
+		}
+
+		for _, value := range values {
+			if v, err := url.QueryUnescape(value); err == nil {
+				value = v
+			}
+
+			query[key] = append(query[key], value)
+		}
+	}
+
+	return query
+}
+
+func multiValues(single map[string]string, multi map[string][]string) map[string][]string {
+	if len(multi) > 0 || len(single) == 0 {
+		return multi
+	}
+
+	values := map[string][]string{}
+	for key, value := range single {
+		values[key] = []string{value}
+	}
+
+	return values
 }
 
 func (r *request) Method() string {
@@ -62,6 +162,24 @@ func (r *request) PathParameter(name string) string {
 	return ""
 }
 
+func (r *request) PathParameterInt(name string) (int, error) {
+	v, err := strconv.Atoi(r.PathParameter(name))
+	if err != nil {
+		return 0, erresponse.InvalidRequestInvalidDataOfName(name)
+	}
+
+	return v, nil
+}
+
+func (r *request) PathParameterUUID(name string) (string, error) {
+	v := r.PathParameter(name)
+	if !uuidPattern.MatchString(v) {
+		return "", erresponse.InvalidRequestInvalidDataOfName(name)
+	}
+
+	return strings.ToLower(v), nil
+}
+
 func (r *request) TraceId() string {
 	return r.GetHeader("x-amzn-trace-id")
 }
@@ -87,7 +205,63 @@ func (r *request) QueryValue(name string) string {
 }
 
 func (r *request) QueryValues(name string) []string {
-	return r.base.MultiValueQueryStringParameters[name]
+	return r.query[name]
+}
+
+// Query returns decoded query, Request().MultiValueQueryStringParameters keeps the form of event source.
+func (r *request) Query() url.Values {
+	return r.query
+}
+
+// RawQuery returns query string still percent-encoded as client sent it.
+func (r *request) RawQuery() string {
+	return r.rawQuery
+}
+
+func (r *request) QueryInt(name string) (int, error) {
+	v, err := strconv.Atoi(r.QueryValue(name))
+	if err != nil {
+		return 0, erresponse.InvalidRequestInvalidDataOfName(name)
+	}
+
+	return v, nil
+}
+
+func (r *request) QueryBool(name string) (bool, error) {
+	v, err := strconv.ParseBool(r.QueryValue(name))
+	if err != nil {
+		return false, erresponse.InvalidRequestInvalidDataOfName(name)
+	}
+
+	return v, nil
+}
+
+// QueryTime parses value by layout, time.RFC3339 is used when layout is empty.
+func (r *request) QueryTime(name string, layout string) (time.Time, error) {
+	if layout == "" {
+		layout = time.RFC3339
+	}
+
+	v, err := time.Parse(layout, r.QueryValue(name))
+	if err != nil {
+		return time.Time{}, erresponse.InvalidRequestInvalidDataOfName(name)
+	}
+
+	return v, nil
+}
+
+// QueryCSV splits every value of name by comma, empty items are dropped.
+func (r *request) QueryCSV(name string) []string {
+	var items []string
+	for _, value := range r.QueryValues(name) {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+
+	return items
 }
 
 func (r *request) Body() buf.ByteBuf {
@@ -111,8 +285,56 @@ func (r *request) UserAgent() string {
 	return r.Header().Get("User-Agent")
 }
 
+func (r *request) Event() any {
+	return r.event
+}
+
+func (r *request) Stage() string {
+	return r.stage
+}
+
+func (r *request) Authorizer() map[string]any {
+	return r.authorizer
+}
+
+func (r *request) Claims() map[string]any {
+	return r.claims
+}
+
+// BindJSON decodes body into v by the Codec and BindOptions of GoLA,
+// invalid body is reported as InvalidRequest and oversize body as PayloadTooLarge.
+func (r *request) BindJSON(v any) error {
+	codec := r.binding.codec
+	if codec == nil {
+		codec = DefaultCodec
+	}
+
+	body := r.Body().Bytes()
+	if limit := r.binding.options.MaxBodySize; limit > 0 && len(body) > limit {
+		return PayloadTooLarge
+	}
+
+	if len(body) == 0 {
+		return erresponse.InvalidRequestWithMessage("empty body")
+	}
+
+	if err := codec.Unmarshal(body, v, r.binding.options.Strict); err != nil {
+		if er, ok := err.(erresponse.ErrorResponse); ok {
+			return er
+		}
+
+		return erresponse.InvalidRequestWithMessage("invalid json: %s", err.Error())
+	}
+
+	return nil
+}
+
 type Response interface {
 	Build() *events.ALBTargetGroupResponse
+	BuildSingleValue() *events.ALBTargetGroupResponse
+	BuildAPIGatewayProxy() *events.APIGatewayProxyResponse
+	BuildAPIGatewayV2HTTP() *events.APIGatewayV2HTTPResponse
+	BuildFunctionURL() *events.LambdaFunctionURLResponse
 	StatusCode() int
 	SetStatusCode(code int) Response
 	AddHeader(name string, value string) Response
@@ -128,50 +350,118 @@ type Response interface {
 	SetBody(buf buf.ByteBuf) Response
 	SetContentType(ct string) Response
 	JSONResponse(buf buf.ByteBuf) Response
+	JSON(code int, v any) error
+	Redirect(location string, code int) error
+	SeeOther(location string) error
 }
 
 type response struct {
 	code    int
 	headers http.Header
 	cookies map[string][]http.Cookie
-	body    buf.ByteBuf
+	// cookieNames keeps the order cookies are set, so Set-Cookie is built deterministically
+	cookieNames []string
+	body        buf.ByteBuf
+	codec       Codec
+	// request and redirectHosts are set by GoLA to resolve Redirect
+	request       Request
+	redirectHosts []string
 }
 
 func NewResponse() Response {
+	return newResponse(DefaultCodec)
+}
+
+func newResponse(codec Codec) *response {
 	return &response{
 		code:    0,
 		headers: map[string][]string{},
 		cookies: map[string][]http.Cookie{},
 		body:    buf.EmptyByteBuf(),
+		codec:   codec,
 	}
 }
 
 func (r *response) Build() *events.ALBTargetGroupResponse {
 	albResp := &events.ALBTargetGroupResponse{}
-	for _, value := range r.cookies {
-		for _, cookie := range value {
-			if v := cookie.String(); v != "" {
-				r.headers.Add("Set-Cookie", v)
-			}
-		}
-	}
-
-	code := r.code
-	if code == 0 {
-		code = 200
+	headers := r.headers.Clone()
+	for _, cookie := range r.cookieStrings() {
+		headers.Add("Set-Cookie", cookie)
 	}
 
 	albResp.Headers = map[string]string{}
-	albResp.MultiValueHeaders = r.headers
-	albResp.StatusCode = code
+	albResp.MultiValueHeaders = headers
+	albResp.StatusCode = r.buildStatusCode()
 	albResp.Body = base64.StdEncoding.EncodeToString(r.body.Bytes())
 	albResp.IsBase64Encoded = true
 	return albResp
 }
 
-func (r *response) Redirect(redirectUrl string) {
-	r.SetStatusCode(httpstatus.Found).
-		SetHeader(httpheadername.Location, redirectUrl)
+func (r *response) BuildSingleValue() *events.ALBTargetGroupResponse {
+	albResp := r.Build()
+	albResp.Headers = joinHeaders(r.headers)
+	if cookies := r.setCookies(); len(cookies) > 0 {
+		// Set-Cookie can not be folded into one line, only the last one set is kept.
+		albResp.Headers[http.CanonicalHeaderKey(httpheadername.SetCookie)] = cookies[len(cookies)-1]
+	}
+
+	albResp.MultiValueHeaders = nil
+	return albResp
+}
+
+func joinHeaders(headers http.Header) map[string]string {
+	joined := map[string]string{}
+	for key, values := range headers {
+		if key != http.CanonicalHeaderKey(httpheadername.SetCookie) {
+			joined[key] = strings.Join(values, ", ")
+		}
+	}
+
+	return joined
+}
+
+func (r *response) buildStatusCode() int {
+	if r.code == 0 {
+		return 200
+	}
+
+	return r.code
+}
+
+// setCookies returns Set-Cookie added to headers followed by cookies set by SetCookie.
+func (r *response) setCookies() []string {
+	return append(append([]string{}, r.headers.Values(httpheadername.SetCookie)...), r.cookieStrings()...)
+}
+
+// cookieStrings returns cookies in the order they are set, cookies put into Cookies() directly follow by name.
+func (r *response) cookieStrings() []string {
+	var cookies, extra []string
+	for name := range r.cookies {
+		if !containsString(r.cookieNames, name) {
+			extra = append(extra, name)
+		}
+	}
+
+	sort.Strings(extra)
+	for _, name := range append(append([]string{}, r.cookieNames...), extra...) {
+		for _, cookie := range r.cookies[name] {
+			if v := cookie.String(); v != "" {
+				cookies = append(cookies, v)
+			}
+		}
+	}
+
+	return cookies
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
 }
 
 func (r *response) StatusCode() int {
@@ -219,6 +509,10 @@ func (r *response) Cookie(name string) *http.Cookie {
 }
 
 func (r *response) SetCookie(cookie http.Cookie) Response {
+	if !containsString(r.cookieNames, cookie.Name) {
+		r.cookieNames = append(r.cookieNames, cookie.Name)
+	}
+
 	r.cookies[cookie.Name] = []http.Cookie{cookie}
 	return r
 }
@@ -246,3 +540,14 @@ func (r *response) JSONResponse(buf buf.ByteBuf) Response {
 		SetHeader(httpheadername.ContentType, "application/json").
 		SetBody(buf)
 }
+
+// JSON encodes v by the Codec of GoLA as response body with status code.
+func (r *response) JSON(code int, v any) error {
+	data, err := r.codec.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	r.SetStatusCode(code).JSONResponse(buf.NewByteBuf(data))
+	return nil
+}
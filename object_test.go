@@ -0,0 +1,59 @@
+package gola
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+type GOLATestQuery struct {
+	Page  int       `query:"page" validate:"min=1"`
+	Tags  []string  `query:"tag"`
+	Since time.Time `query:"since"`
+	Name  string    `json:"name"`
+}
+
+func TestRequest_Query(t *testing.T) {
+	req := NewRequest(events.ALBTargetGroupRequest{MultiValueQueryStringParameters: map[string][]string{
+		"q": {"a%20b+c"}, "na%6De": {"kk"}, "page": {"2"}, "debug": {"true"}, "since": {"2023-01-02T03%3A04%3A05Z"},
+		"tag": {"a,%20b", "c"}, "bad": {"%zz"},
+	}}, nil)
+
+	assert.Equal(t, "a b c", req.QueryValue("q"))
+	assert.Equal(t, "kk", req.QueryValue("name"))
+	assert.Equal(t, "%zz", req.QueryValue("bad"))
+	assert.Equal(t, []string{"a%20b+c"}, req.Request().MultiValueQueryStringParameters["q"])
+	assert.Equal(t, "bad=%zz&debug=true&na%6De=kk&page=2&q=a%20b+c&since=2023-01-02T03%3A04%3A05Z&tag=a,%20b&tag=c", req.RawQuery())
+	page, err := req.QueryInt("page")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, page)
+	_, err = req.QueryInt("q")
+	assert.NotNil(t, err)
+	debug, err := req.QueryBool("debug")
+	assert.Nil(t, err)
+	assert.True(t, debug)
+	since, err := req.QueryTime("since", "")
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC), since)
+	assert.Equal(t, []string{"a", "b", "c"}, req.QueryCSV("tag"))
+
+	query := &GOLATestQuery{Name: "keep"}
+	assert.Nil(t, req.BindQuery(query))
+	assert.Equal(t, 2, query.Page)
+	assert.Equal(t, []string{"a, b", "c"}, query.Tags)
+	assert.Equal(t, since, query.Since)
+	assert.Equal(t, "keep", query.Name)
+
+	req = NewRequest(events.ALBTargetGroupRequest{MultiValueQueryStringParameters: map[string][]string{"page": {"0"}}}, nil)
+	assert.NotNil(t, req.BindQuery(&GOLATestQuery{}))
+
+	// API Gateway decodes query already, it must not be decoded twice
+	req = NewAPIGatewayProxyRequest(events.APIGatewayProxyRequest{MultiValueQueryStringParameters: map[string][]string{"q": {"100%25 a+b"}}}, nil)
+	assert.Equal(t, "100%25 a+b", req.QueryValue("q"))
+	assert.Equal(t, "q=100%2525+a%2Bb", req.RawQuery())
+	req = NewAPIGatewayV2HTTPRequest(events.APIGatewayV2HTTPRequest{RawQueryString: "q=100%2525"}, nil)
+	assert.Equal(t, "100%25", req.QueryValue("q"))
+	assert.Equal(t, "q=100%2525", req.RawQuery())
+}
@@ -0,0 +1,170 @@
+package gola
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	httpheadername "github.com/kklab-com/gone-httpheadername"
+	httpstatus "github.com/kklab-com/gone-httpstatus"
+)
+
+// PathPolicy controls path normalization before routing.
+//
+// Clean drops empty and "." segments and resolves "..", escaped dots included.
+// CaseInsensitive matches static segments ignoring case, the registered case is canonical.
+// Redirect answers a non-canonical path by redirect to the canonical one instead of serving it,
+// 301 for GET and HEAD, 308 for other methods so body is resent.
+// TrailingSlash decides how a path ending with "/" is served, root path is never redirected.
+type PathPolicy struct {
+	Clean           bool
+	CaseInsensitive bool
+	Redirect        bool
+	TrailingSlash   TrailingSlash
+}
+
+type TrailingSlash int
+
+const (
+	// TrailingSlashIgnore serves `/user` and `/user/` by the same endpoint.
+	TrailingSlashIgnore TrailingSlash = iota
+	// TrailingSlashRedirectToSlash redirects `/user` to `/user/`.
+	TrailingSlashRedirectToSlash
+	// TrailingSlashRedirectToNoSlash redirects `/user/` to `/user`.
+	TrailingSlashRedirectToNoSlash
+	// TrailingSlashStrict serves a path only in the form it is registered, `/user` answers `/user/` by 404
+	// and `/user/` answers `/user` by 404. Both forms share one endpoint, so they can not have different handlers.
+	TrailingSlashStrict
+)
+
+func hasTrailingSlash(path string) bool {
+	return len(path) > 1 && strings.HasSuffix(path, "/")
+}
+
+// redirectPath returns the path request should be redirected to by policy, path is the normalized request path.
+func (p PathPolicy) redirectPath(request Request, node *_Node, isLast bool, path string) (string, bool) {
+	target, redirect := trimTrailingSlash(path), false
+	if p.Redirect {
+		target = canonicalPath(node, isLast, path)
+		redirect = target != trimTrailingSlash(request.Path())
+	}
+
+	slash := hasTrailingSlash(request.Path())
+	switch p.TrailingSlash {
+	case TrailingSlashRedirectToSlash:
+		redirect = redirect || (!slash && target != "/")
+		slash = true
+	case TrailingSlashRedirectToNoSlash:
+		redirect = redirect || slash
+		slash = false
+	}
+
+	if slash && target != "/" {
+		target += "/"
+	}
+
+	return target, redirect
+}
+
+func cleanPath(path string) string {
+	if strings.HasPrefix(path, "/") && !strings.Contains(path, "//") && !strings.Contains(path, "/.") &&
+		!strings.Contains(path, "%2e") && !strings.Contains(path, "%2E") {
+		return path
+	}
+
+	var segments []string
+	for _, segment := range strings.Split(path, "/") {
+		switch unescapeSegment(segment) {
+		case "", ".":
+			continue
+		case "..":
+			if len(segments) > 0 {
+				segments = segments[:len(segments)-1]
+			}
+
+			continue
+		}
+
+		segments = append(segments, segment)
+	}
+
+	cleaned := "/" + strings.Join(segments, "/")
+	if strings.HasSuffix(path, "/") && cleaned != "/" {
+		cleaned += "/"
+	}
+
+	return cleaned
+}
+
+// canonicalPath rebuilds path by the registered static segments of node, parameter segments are kept as requested.
+func canonicalPath(node *_Node, isLast bool, path string) string {
+	// reversed pattern, "" is a parameter segment and "*" is the rest of path
+	var pattern []string
+	switch {
+	case node.nodeType == NodeTypeRecursive:
+		pattern = append(pattern, "*")
+	case node.declared && !isLast:
+		pattern = append(pattern, "")
+	}
+
+	for current := node; current.parent != nil; current = current.parent.(*_Node) {
+		pattern = append(pattern, current.name)
+		if current.parameterChild {
+			pattern = append(pattern, "")
+		}
+	}
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	var canonical []string
+	for i := len(pattern) - 1; i >= 0 && len(canonical) < len(segments); i-- {
+		switch pattern[i] {
+		case "*":
+			canonical = append(canonical, segments[len(canonical):]...)
+		case "":
+			canonical = append(canonical, segments[len(canonical)])
+		default:
+			canonical = append(canonical, pattern[i])
+		}
+	}
+
+	return "/" + strings.Join(canonical, "/")
+}
+
+func trimTrailingSlash(path string) string {
+	if trimmed := strings.TrimRight(path, "/"); trimmed != "" {
+		return trimmed
+	}
+
+	return "/"
+}
+
+// redirectPermanently redirects to path with query of request kept as sent, leading slashes are collapsed
+// and backslash, space and control characters are escaped, so the Location can not become a protocol-relative url.
+func redirectPermanently(request Request, response Response, path string) {
+	path = "/" + strings.TrimLeft(path, "/")
+	if query := request.RawQuery(); query != "" {
+		path = path + "?" + query
+	}
+
+	path = escapeLocation(path)
+
+	code := httpstatus.PermanentRedirect
+	if request.Method() == http.MethodGet || request.Method() == http.MethodHead {
+		code = httpstatus.MovedPermanently
+	}
+
+	response.SetStatusCode(code).SetHeader(httpheadername.Location, path)
+}
+
+func escapeLocation(location string) string {
+	var escaped strings.Builder
+	for i := 0; i < len(location); i++ {
+		if c := location[i]; c == '\\' || c <= ' ' || c == 0x7f {
+			fmt.Fprintf(&escaped, "%%%02X", c)
+		} else {
+			escaped.WriteByte(c)
+		}
+	}
+
+	return escaped.String()
+}
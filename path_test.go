@@ -0,0 +1,152 @@
+package gola
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCleanPath(t *testing.T) {
+	assert.Equal(t, "/user/123", cleanPath("/user/123"))
+	assert.Equal(t, "/user/123/", cleanPath("//user//123/"))
+	assert.Equal(t, "/book", cleanPath("/user/./../book"))
+	assert.Equal(t, "/book", cleanPath("/user/%2E%2e/book"))
+	assert.Equal(t, "/", cleanPath("/../.."))
+	assert.Equal(t, "/user/a%2Fb", cleanPath("/user/./a%2Fb"))
+}
+
+func TestRoute_RouteNodeUnescape(t *testing.T) {
+	route := NewRoute()
+	route.
+		SetEndpoint("/user/:user_id", &TestDefaultEmptyHandler{}).
+		SetEndpoint("/user/:user_id/book/:book", &TestDefaultEmptyHandler{}).
+		SetEndpoint("/files/*", &TestDefaultEmptyHandler{})
+
+	_, parameters, _ := route.RouteNode("/user/john%20doe")
+	assert.Equal(t, "john doe", parameters["user_id"])
+	_, parameters, _ = route.RouteNode("/user/a%2Fb/b%6Fok/c%2Fd")
+	assert.Equal(t, "a/b", parameters["user_id"])
+	assert.Equal(t, "c/d", parameters["book"])
+	_, parameters, _ = route.RouteNode("/%75ser/%zz")
+	assert.Equal(t, "%zz", parameters["user_id"])
+	_, parameters, _ = route.RouteNode("/files/a%20b/c")
+	assert.Equal(t, "a b/c", parameters["files"])
+	node, _, _ := route.RouteNode("/USER/123")
+	assert.Nil(t, node)
+}
+
+func TestGoLA_PathPolicy(t *testing.T) {
+	goLA := NewServe()
+	goLA.Route().
+		SetEndpoint("/User/:user_id", &TestDefaultEmptyHandler{}).
+		SetEndpoint("/files/*", &TestDefaultEmptyHandler{})
+
+	send := func(method string, path string) events.ALBTargetGroupResponse {
+		response, err := goLA.Register(context.Background(), events.ALBTargetGroupRequest{Path: path, HTTPMethod: method,
+			MultiValueQueryStringParameters: map[string][]string{"q": {"a%20b"}, "b": {"x+y"}}})
+		assert.Nil(t, err)
+		return response
+	}
+
+	assert.Equal(t, 200, send("GET", "//User/./123").StatusCode)
+	assert.Equal(t, 200, send("GET", "/files/../User/123").StatusCode)
+	assert.Equal(t, 404, send("GET", "/user/123").StatusCode)
+
+	goLA.PathPolicy.CaseInsensitive = true
+	assert.Equal(t, 200, send("GET", "/user/123").StatusCode)
+
+	goLA.PathPolicy.Redirect = true
+	assert.Equal(t, 200, send("GET", "/User/john%20doe").StatusCode)
+	assert.Equal(t, 200, send("GET", "/files/A/b").StatusCode)
+	response := send("GET", "/user/./john%20doe")
+	assert.Equal(t, 301, response.StatusCode)
+	assert.Equal(t, []string{"/User/john%20doe?b=x+y&q=a%20b"}, response.MultiValueHeaders["Location"])
+	response = send("POST", "//files/a/../B")
+	assert.Equal(t, 308, response.StatusCode)
+	assert.Equal(t, []string{"/files/B?b=x+y&q=a%20b"}, response.MultiValueHeaders["Location"])
+
+	v2, err := goLA.RegisterAPIGatewayV2HTTP(context.Background(), events.APIGatewayV2HTTPRequest{RawPath: "/user/1", RawQueryString: "z=1&a=%20&a=b+c",
+		RequestContext: events.APIGatewayV2HTTPRequestContext{HTTP: events.APIGatewayV2HTTPRequestContextHTTPDescription{Method: "GET"}}})
+	assert.Nil(t, err)
+	assert.Equal(t, 301, v2.StatusCode)
+	assert.Equal(t, "/User/1?z=1&a=%20&a=b+c", v2.Headers["Location"])
+}
+
+func TestGoLA_TrailingSlash(t *testing.T) {
+	goLA := NewServe()
+	goLA.Route().SetEndpoint("/auth/group/user", &TestDefaultEmptyHandler{})
+	send := func(method string, path string) events.ALBTargetGroupResponse {
+		response, err := goLA.Register(context.Background(), events.ALBTargetGroupRequest{Path: path, HTTPMethod: method,
+			MultiValueQueryStringParameters: map[string][]string{"page": {"2"}}})
+		assert.Nil(t, err)
+		return response
+	}
+
+	assert.Equal(t, 200, send("GET", "/auth/group/user").StatusCode)
+	assert.Equal(t, 200, send("GET", "/auth/group/user/").StatusCode)
+
+	goLA.PathPolicy.TrailingSlash = TrailingSlashRedirectToSlash
+	response := send("GET", "/auth/group/user")
+	assert.Equal(t, 301, response.StatusCode)
+	assert.Equal(t, []string{"/auth/group/user/?page=2"}, response.MultiValueHeaders["Location"])
+	assert.Equal(t, 200, send("GET", "/auth/group/user/").StatusCode)
+	assert.Equal(t, 200, send("GET", "/").StatusCode)
+	assert.Equal(t, 404, send("GET", "/none").StatusCode)
+
+	goLA.PathPolicy.TrailingSlash = TrailingSlashRedirectToNoSlash
+	response = send("PUT", "/auth/group/user/")
+	assert.Equal(t, 308, response.StatusCode)
+	assert.Equal(t, []string{"/auth/group/user?page=2"}, response.MultiValueHeaders["Location"])
+	assert.Equal(t, 200, send("GET", "/auth/group/user").StatusCode)
+	response = send("GET", "//auth//group/user/")
+	assert.Equal(t, 301, response.StatusCode)
+	assert.Equal(t, []string{"/auth/group/user?page=2"}, response.MultiValueHeaders["Location"])
+
+	goLA.PathPolicy.TrailingSlash = TrailingSlashStrict
+	assert.Equal(t, 404, send("GET", "/auth/group/user/").StatusCode)
+	assert.Equal(t, 200, send("GET", "/auth/group/user").StatusCode)
+	assert.Equal(t, 200, send("GET", "/").StatusCode)
+
+	goLA.Route().Group("/x").SetEndpoint("/", &TestDefaultEmptyHandler{})
+	goLA.Route().Get("/book/:book/", &TestDefaultEmptyHandler{})
+	goLA.Route().Named("files").Strict().SetEndpoint("/files/*", &TestDefaultEmptyHandler{})
+	assert.Empty(t, goLA.Route().Validate())
+	assert.Equal(t, 200, send("GET", "/x/").StatusCode)
+	assert.Equal(t, 404, send("GET", "/x").StatusCode)
+	assert.Equal(t, 200, send("GET", "/book/1/").StatusCode)
+	assert.Equal(t, 404, send("GET", "/book/1").StatusCode)
+	assert.Equal(t, 200, send("GET", "/book/").StatusCode)
+	assert.Equal(t, 404, send("GET", "/book").StatusCode)
+	assert.Equal(t, 200, send("GET", "/files/a/b").StatusCode)
+	assert.Equal(t, 404, send("GET", "/files/a/b/").StatusCode)
+
+	goLA.PathPolicy.TrailingSlash = TrailingSlashIgnore
+	assert.Equal(t, 200, send("GET", "/x").StatusCode)
+	assert.Equal(t, 200, send("GET", "/book/1").StatusCode)
+}
+
+func TestGoLA_RedirectEscape(t *testing.T) {
+	goLA := NewServe()
+	goLA.Route().SetEndpoint("/:slug", &TestDefaultEmptyHandler{})
+	goLA.PathPolicy.TrailingSlash = TrailingSlashRedirectToNoSlash
+	send := func(path string) events.ALBTargetGroupResponse {
+		response, err := goLA.Register(context.Background(), events.ALBTargetGroupRequest{Path: path, HTTPMethod: "GET"})
+		assert.Nil(t, err)
+		return response
+	}
+
+	response := send("/\\evil.com/")
+	assert.Equal(t, 301, response.StatusCode)
+	assert.Equal(t, []string{"/%5Cevil.com"}, response.MultiValueHeaders["Location"])
+	response = send("//\\evil.com/")
+	assert.Equal(t, []string{"/%5Cevil.com"}, response.MultiValueHeaders["Location"])
+	response = send("/a\r\nb c\t/")
+	assert.Equal(t, []string{"/a%0D%0Ab%20c%09"}, response.MultiValueHeaders["Location"])
+
+	goLA.PathPolicy = PathPolicy{Clean: true, Redirect: true}
+	response = send("/./\\evil.com")
+	assert.Equal(t, 301, response.StatusCode)
+	assert.Equal(t, []string{"/%5Cevil.com"}, response.MultiValueHeaders["Location"])
+}
@@ -0,0 +1,114 @@
+package gola
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	buf "github.com/kklab-com/goth-bytebuf"
+	erresponse "github.com/kklab-com/goth-erresponse"
+)
+
+const ProblemContentType = "application/problem+json"
+
+// ProblemDetails is the RFC 7807 document, Extensions are inlined as members.
+type ProblemDetails struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Extensions map[string]any
+}
+
+func (p *ProblemDetails) MarshalJSON() ([]byte, error) {
+	doc := map[string]any{}
+	for key, value := range p.Extensions {
+		doc[key] = value
+	}
+
+	doc["type"] = p.Type
+	doc["title"] = p.Title
+	doc["status"] = p.Status
+	if p.Detail != "" {
+		doc["detail"] = p.Detail
+	}
+
+	if p.Instance != "" {
+		doc["instance"] = p.Instance
+	}
+
+	return json.Marshal(doc)
+}
+
+// ProblemDetailsRenderer renders errors as application/problem+json,
+// IncludeTrace exposes panic trace and should be disabled in production.
+type ProblemDetailsRenderer struct {
+	TypeBaseURL  string
+	IncludeTrace bool
+}
+
+func (p *ProblemDetailsRenderer) Problem(ctx context.Context, request Request, err erresponse.ErrorResponse) *ProblemDetails {
+	problem := &ProblemDetails{
+		Type:       "about:blank",
+		Title:      err.ErrorDescription(),
+		Status:     err.ErrorStatusCode(),
+		Detail:     err.Message(),
+		Extensions: map[string]any{},
+	}
+
+	if problem.Status == 0 {
+		problem.Status = http.StatusInternalServerError
+	}
+
+	if p.TypeBaseURL != "" && err.ErrorName() != "" {
+		problem.Type = p.TypeBaseURL + err.ErrorName()
+	}
+
+	if problem.Title == "" {
+		problem.Title = http.StatusText(problem.Status)
+	}
+
+	if v := err.ErrorName(); v != "" {
+		problem.Extensions["error"] = v
+	}
+
+	if v := err.Code(); v != "" {
+		problem.Extensions["error_code"] = v
+	}
+
+	if v := err.ErrorData(); len(v) > 0 {
+		problem.Extensions["data"] = v
+	}
+
+	if request != nil {
+		problem.Instance = request.Path()
+		if v := request.TraceId(); v != "" {
+			problem.Extensions["trace_id"] = v
+		}
+	}
+
+	if p.IncludeTrace {
+		if impl, ok := err.(*ErrorResponseImpl); ok && impl.Caught != nil {
+			problem.Extensions["caught"] = impl.Caught
+		} else if caught := ctx.Value(CtxGoLAPanic); caught != nil {
+			problem.Extensions["caught"] = caught
+		}
+	}
+
+	return problem
+}
+
+func (p *ProblemDetailsRenderer) RenderError(ctx context.Context, request Request, response Response, err erresponse.ErrorResponse) {
+	problem := p.Problem(ctx, request, err)
+	body, e := json.Marshal(problem)
+	if e != nil {
+		wrapErrorResponse(err, response)
+		return
+	}
+
+	response.
+		SetStatusCode(problem.Status).
+		SetContentType(ProblemContentType).
+		SetBody(buf.NewByteBuf(body))
+}
@@ -0,0 +1,63 @@
+package gola
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	erresponse "github.com/kklab-com/goth-erresponse"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProblemDetailsRenderer(t *testing.T) {
+	goLA := NewServe()
+	goLA.ErrorRenderer = &ProblemDetailsRenderer{TypeBaseURL: "https://errors.example.com/"}
+	goLA.MapError(ErrorIs(GOLATestErrNoQuota, erresponse.SlowDownTooFast))
+	goLA.Route().SetEndpoint("/quota", HandlerFunc(func(ctx context.Context, request Request, response Response) error {
+		return fmt.Errorf("charge: %w", GOLATestErrNoQuota)
+	}))
+
+	goLA.Route().SetEndpoint("/panic", HandlerFunc(func(ctx context.Context, request Request, response Response) error {
+		panic("boom")
+	}))
+
+	problem := func(resp events.ALBTargetGroupResponse) map[string]any {
+		body, _ := base64.StdEncoding.DecodeString(resp.Body)
+		doc := map[string]any{}
+		assert.Nil(t, json.Unmarshal(body, &doc))
+		return doc
+	}
+
+	response, err := goLA.Register(context.Background(), events.ALBTargetGroupRequest{Path: "/quota", HTTPMethod: "GET",
+		MultiValueHeaders: map[string][]string{"X-Amzn-Trace-Id": {"Root=1-abc"}}})
+	assert.Nil(t, err)
+	assert.Equal(t, erresponse.SlowDownTooFast.ErrorStatusCode(), response.StatusCode)
+	assert.Equal(t, ProblemContentType, response.MultiValueHeaders["Content-Type"][0])
+	doc := problem(response)
+	assert.Equal(t, "https://errors.example.com/"+erresponse.SlowDownTooFast.ErrorName(), doc["type"])
+	assert.EqualValues(t, response.StatusCode, doc["status"])
+	assert.Equal(t, "/quota", doc["instance"])
+	assert.Equal(t, "Root=1-abc", doc["trace_id"])
+	assert.Equal(t, erresponse.SlowDownTooFast.Code(), doc["error_code"])
+
+	response, err = goLA.Register(context.Background(), events.ALBTargetGroupRequest{Path: "/none", HTTPMethod: "GET"})
+	assert.Nil(t, err)
+	assert.Equal(t, 404, response.StatusCode)
+	assert.Equal(t, ProblemContentType, response.MultiValueHeaders["Content-Type"][0])
+	assert.Equal(t, "/none", problem(response)["instance"])
+
+	response, err = goLA.Register(context.Background(), events.ALBTargetGroupRequest{Path: "/panic", HTTPMethod: "GET"})
+	assert.Nil(t, err)
+	assert.Equal(t, 500, response.StatusCode)
+	assert.Nil(t, problem(response)["caught"])
+
+	goLA.ErrorRenderer = &ProblemDetailsRenderer{IncludeTrace: true}
+	response, err = goLA.Register(context.Background(), events.ALBTargetGroupRequest{Path: "/panic", HTTPMethod: "GET"})
+	assert.Nil(t, err)
+	doc = problem(response)
+	assert.Equal(t, "about:blank", doc["type"])
+	assert.NotNil(t, doc["caught"])
+}
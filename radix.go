@@ -0,0 +1,213 @@
+package gola
+
+import (
+	"net/url"
+	"strings"
+)
+
+// radixNode is the compiled form of a _Node, single-child namespace chains are
+// compressed into one edge so static paths are matched with few lookups.
+type radixNode struct {
+	node               *_Node
+	matchable          bool
+	statics            map[string]*radixEdge
+	parameterEdges     map[string]*radixEdge
+	foldStatics        map[string]*radixEdge
+	foldParameterEdges map[string]*radixEdge
+}
+
+type radixEdge struct {
+	prefix string
+	next   *radixNode
+}
+
+func compileRadix(node *_Node) *radixNode {
+	rn := &radixNode{
+		node:      node,
+		matchable: node.nodeType != NodeTypeNamespace,
+	}
+
+	for name, child := range node.children {
+		edge := compileEdge(name, child.(*_Node))
+		if child.(*_Node).parameterChild {
+			rn.parameterEdges = addEdge(rn.parameterEdges, name, edge)
+			rn.foldParameterEdges = addEdge(rn.foldParameterEdges, strings.ToLower(name), edge)
+		} else {
+			rn.statics = addEdge(rn.statics, name, edge)
+			rn.foldStatics = addEdge(rn.foldStatics, strings.ToLower(name), edge)
+		}
+	}
+
+	return rn
+}
+
+func addEdge(edges map[string]*radixEdge, name string, edge *radixEdge) map[string]*radixEdge {
+	if edges == nil {
+		edges = map[string]*radixEdge{}
+	}
+
+	if _, f := edges[name]; !f {
+		edges[name] = edge
+	}
+
+	return edges
+}
+
+// lookup finds the edge of segment, fold falls back to case-insensitive match.
+func lookup(edges map[string]*radixEdge, foldEdges map[string]*radixEdge, segment string, fold bool) (*radixEdge, bool) {
+	if edge, f := edges[segment]; f {
+		return edge, true
+	}
+
+	if fold {
+		edge, f := foldEdges[strings.ToLower(segment)]
+		return edge, f
+	}
+
+	return nil, false
+}
+
+func compileEdge(name string, node *_Node) *radixEdge {
+	prefix := name
+	for node.nodeType == NodeTypeNamespace && !node.declared && len(node.handlers) == 0 && len(node.methodHandlers) == 0 && len(node.children) == 1 {
+		var child *_Node
+		for _, c := range node.children {
+			child = c.(*_Node)
+		}
+
+		if child.parameterChild {
+			break
+		}
+
+		prefix = prefix + "/" + child.name
+		node = child
+	}
+
+	return &radixEdge{prefix: prefix, next: compileRadix(node)}
+}
+
+// consume strips the edge prefix from path, the prefix must end on a segment boundary.
+// Escaped or case-folded segments are compared one by one.
+func (e *radixEdge) consume(path string, fold bool) (string, bool) {
+	if strings.HasPrefix(path, e.prefix) {
+		rest := path[len(e.prefix):]
+		if rest == "" {
+			return rest, true
+		}
+
+		if rest[0] == '/' {
+			return rest[1:], true
+		}
+	}
+
+	if !fold && strings.IndexByte(path, '%') < 0 {
+		return "", false
+	}
+
+	prefix, rest := e.prefix, path
+	for prefix != "" {
+		var want, got string
+		want, prefix = cutSegment(prefix)
+		got, rest = cutSegment(rest)
+		if got = unescapeSegment(got); got != want && !(fold && strings.EqualFold(got, want)) {
+			return "", false
+		}
+	}
+
+	return rest, true
+}
+
+// unescapeSegment decodes a path segment, malformed escape is kept as is.
+func unescapeSegment(segment string) string {
+	if strings.IndexByte(segment, '%') < 0 {
+		return segment
+	}
+
+	if v, err := url.PathUnescape(segment); err == nil {
+		return v
+	}
+
+	return segment
+}
+
+func cutSegment(path string) (segment string, rest string) {
+	if idx := strings.IndexByte(path, '/'); idx >= 0 {
+		return path[:idx], path[idx+1:]
+	}
+
+	return path, ""
+}
+
+// match resolves path under n, static segments take precedence over the parameter,
+// the parameter takes precedence over the catch-all, and a failed branch falls back to the next one.
+// Segments are unescaped after splitting so an escaped slash stays inside the parameter,
+// fold matches static segments case-insensitively.
+func (n *radixNode) match(path string, params map[string]string, fold bool) (*radixNode, map[string]string, bool) {
+	if path == "" {
+		if n.matchable {
+			return n, params, true
+		}
+
+		return nil, params, false
+	}
+
+	raw, rest := cutSegment(path)
+	segment := unescapeSegment(raw)
+	if edge, f := lookup(n.statics, n.foldStatics, segment, fold); f {
+		if remain, ok := edge.consume(path, fold); ok {
+			matched, p, isLast := edge.next.match(remain, params, fold)
+			if matched != nil {
+				return matched, p, isLast
+			}
+
+			params = p
+		}
+	}
+
+	if n.node.declared && n.node.nodeType != NodeTypeRecursive && n.node.matchParameter(segment) {
+		if rest == "" {
+			return n, setParameter(params, n.node.parameterName, segment), false
+		}
+
+		next, _ := cutSegment(rest)
+		if edge, f := lookup(n.parameterEdges, n.foldParameterEdges, unescapeSegment(next), fold); f {
+			if remain, ok := edge.consume(rest, fold); ok {
+				params = setParameter(params, n.node.parameterName, segment)
+				matched, p, isLast := edge.next.match(remain, params, fold)
+				if matched != nil {
+					return matched, p, isLast
+				}
+
+				params = p
+				delete(params, n.node.parameterName)
+			}
+		}
+	}
+
+	if n.node.nodeType == NodeTypeRecursive {
+		return n, setParameter(params, n.node.parameterName, unescapePath(path)), false
+	}
+
+	return nil, params, false
+}
+
+func unescapePath(path string) string {
+	if strings.IndexByte(path, '%') < 0 {
+		return path
+	}
+
+	if v, err := url.PathUnescape(path); err == nil {
+		return v
+	}
+
+	return path
+}
+
+func setParameter(params map[string]string, name string, value string) map[string]string {
+	if params == nil {
+		params = map[string]string{}
+	}
+
+	params[name] = value
+	return params
+}
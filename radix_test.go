@@ -0,0 +1,169 @@
+package gola
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoute_RadixPrecedence(t *testing.T) {
+	route := NewRoute()
+	route.
+		SetEndpoint("/user/:user_id", &TestDefaultEmptyHandler{}).
+		SetEndpoint("/user/me", &TestDefaultEmptyHandler{}).
+		SetEndpoint("/user/:user_id/book/:book", &TestDefaultEmptyHandler{}).
+		SetEndpoint("/user/:user_id/files/*", &TestDefaultEmptyHandler{}).
+		SetEndpoint("/static/*", &TestDefaultEmptyHandler{}).
+		SetEndpoint("/static/index", &TestDefaultEmptyHandler{}).
+		SetEndpoint("/a/b/c/d", &TestDefaultEmptyHandler{})
+
+	node, parameters, isLast := route.RouteNode("/user/me")
+	assert.Equal(t, "me", node.Name())
+	assert.Nil(t, parameters)
+	assert.True(t, isLast)
+
+	node, parameters, isLast = route.RouteNode("/user/you")
+	assert.Equal(t, "user", node.Name())
+	assert.Equal(t, "you", parameters["user_id"])
+	assert.False(t, isLast)
+
+	node, parameters, isLast = route.RouteNode("/user/me/book/b1")
+	assert.Equal(t, "book", node.Name())
+	assert.Equal(t, "me", parameters["user_id"])
+	assert.Equal(t, "b1", parameters["book"])
+	assert.False(t, isLast)
+
+	node, parameters, _ = route.RouteNode("/user/123/files/a/b/c.txt")
+	assert.Equal(t, NodeTypeRecursive, node.NodeType())
+	assert.Equal(t, "123", parameters["user_id"])
+	assert.Equal(t, "a/b/c.txt", parameters["files"])
+
+	node, _, isLast = route.RouteNode("/static/index")
+	assert.Equal(t, "index", node.Name())
+	assert.True(t, isLast)
+	node, parameters, _ = route.RouteNode("/static/index/more")
+	assert.Equal(t, "static", node.Name())
+	assert.Equal(t, "index/more", parameters["static"])
+
+	assert.Nil(t, route.FindNode("/user/book/b1"))
+	assert.Nil(t, route.FindNode("/a/b"))
+	assert.Nil(t, route.FindNode("/a/b/c/d/e"))
+	assert.Nil(t, route.FindNode("/a/bb/c/d"))
+	assert.Equal(t, "d", route.FindNode("/a/b/c/d").Name())
+
+	route.SetEndpoint("/a/b", &TestDefaultEmptyHandler{})
+	assert.Equal(t, "b", route.FindNode("/a/b").Name())
+}
+
+func TestRoute_RadixStaticNoAlloc(t *testing.T) {
+	route := benchmarkRoute()
+	allocs := testing.AllocsPerRun(100, func() {
+		route.RouteNode("/auth/group/user/profile")
+	})
+
+	assert.Equal(t, float64(0), allocs)
+}
+
+func benchmarkRoute() *Route {
+	route := NewRoute()
+	route.
+		SetEndpoint("/auth/group/user/:user_id", &TestDefaultEmptyHandler{}).
+		SetEndpoint("/auth/group/user/:user_id/book/:book", &TestDefaultEmptyHandler{}).
+		SetEndpoint("/auth/group/user/profile", &TestDefaultEmptyHandler{}).
+		SetEndpoint("/auth/group/admin/:admin_id", &TestDefaultEmptyHandler{}).
+		SetEndpoint("/wild/*", &TestDefaultEmptyHandler{})
+	route.RouteNode("/")
+	return route
+}
+
+// legacyRouteNode is the matcher before radix compilation, kept for benchmark comparison.
+func legacyRouteNode(root Node, path string) (node Node, parameters map[string]string, isLast bool) {
+	path = strings.TrimLeft(strings.TrimRight(path, "/"), "/")
+	params := map[string]string{}
+	if path == "" {
+		return root, nil, true
+	}
+
+	parts := strings.Split(path, "/")
+	nodeLens := len(parts)
+	current := root
+	next := root
+	for idx, part := range parts {
+		next = current.Children()[part]
+		switch current.NodeType() {
+		case NodeTypeRoot, NodeTypeEndPoint:
+			if idx+1 == nodeLens {
+				if next == nil {
+					if current == root && part != "" {
+						return nil, nil, false
+					} else {
+						params[current.ParameterName()] = part
+						return current, params, false
+					}
+				} else {
+					return next, params, true
+				}
+			} else {
+				if next == nil {
+					if _, f := current.Children()[parts[idx+1]]; f {
+						params[current.ParameterName()] = part
+						continue
+					} else {
+						return nil, nil, false
+					}
+				} else {
+					current = next
+				}
+			}
+		case NodeTypeRecursive:
+			if next == nil {
+				params[current.ParameterName()] = part
+			}
+
+			return current, params, false
+		case NodeTypeNamespace:
+			if next == nil {
+				return nil, nil, false
+			}
+
+			current = next
+		}
+	}
+
+	if current.NodeType() == NodeTypeNamespace {
+		return nil, nil, false
+	}
+
+	return current, params, current == next
+}
+
+var benchmarkPaths = map[string]string{
+	"Static":    "/auth/group/user/profile",
+	"Parameter": "/auth/group/user/123/book/book1",
+	"Wildcard":  "/wild/card/new",
+}
+
+func BenchmarkRoute_RouteNode(b *testing.B) {
+	route := benchmarkRoute()
+	for name, path := range benchmarkPaths {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				route.RouteNode(path)
+			}
+		})
+	}
+}
+
+func BenchmarkRoute_LegacyRouteNode(b *testing.B) {
+	route := benchmarkRoute()
+	for name, path := range benchmarkPaths {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				legacyRouteNode(route.root, path)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package gola
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	httpheadername "github.com/kklab-com/gone-httpheadername"
+	httpstatus "github.com/kklab-com/gone-httpstatus"
+	erresponse "github.com/kklab-com/goth-erresponse"
+)
+
+var ErrRedirectNotAllowed = erresponse.InvalidRequestWithMessage("redirect target is not allowed")
+
+// Redirect sets Location and code which must be one of 300, 301, 302, 303, 307 and 308.
+// Relative location is resolved against X-Forwarded-Proto and X-Forwarded-Host (or Host) of request,
+// absolute location must point to request host or GoLA.RedirectHosts, otherwise ErrRedirectNotAllowed is returned.
+func (r *response) Redirect(location string, code int) error {
+	switch code {
+	case httpstatus.MultipleChoices, httpstatus.MovedPermanently, httpstatus.Found, httpstatus.SeeOther,
+		httpstatus.TemporaryRedirect, httpstatus.PermanentRedirect:
+	default:
+		return fmt.Errorf("gola: invalid redirect code %d", code)
+	}
+
+	target, err := r.resolveRedirect(location)
+	if err != nil {
+		return err
+	}
+
+	r.SetStatusCode(code).SetHeader(httpheadername.Location, target)
+	return nil
+}
+
+// SeeOther redirects by 303 so client follows it by GET, it is used after POST.
+func (r *response) SeeOther(location string) error {
+	return r.Redirect(location, httpstatus.SeeOther)
+}
+
+func (r *response) resolveRedirect(location string) (string, error) {
+	if location == "" || strings.ContainsAny(location, "\\\r\n") {
+		return "", ErrRedirectNotAllowed
+	}
+
+	target, err := url.Parse(location)
+	if err != nil {
+		return "", ErrRedirectNotAllowed
+	}
+
+	if target.Scheme != "" || target.Host != "" {
+		if (target.Scheme != "http" && target.Scheme != "https") || !r.allowRedirectHost(target.Hostname()) {
+			return "", ErrRedirectNotAllowed
+		}
+
+		return target.String(), nil
+	}
+
+	base := r.requestURL()
+	if base == nil || (len(r.redirectHosts) > 0 && !matchHost(r.redirectHosts, base.Hostname())) {
+		return target.String(), nil
+	}
+
+	return base.ResolveReference(target).String(), nil
+}
+
+func (r *response) requestURL() *url.URL {
+	if r.request == nil {
+		return nil
+	}
+
+	host := firstValue(r.request.GetHeader(httpheadername.XForwardedHost))
+	if host == "" {
+		host = r.request.GetHeader(httpheadername.Host)
+	}
+
+	if host == "" {
+		return nil
+	}
+
+	scheme := strings.ToLower(firstValue(r.request.GetHeader(httpheadername.XForwardedProto)))
+	if scheme != "http" {
+		scheme = "https"
+	}
+
+	return &url.URL{Scheme: scheme, Host: host, Path: r.request.Path()}
+}
+
+func (r *response) allowRedirectHost(host string) bool {
+	if base := r.requestURL(); base != nil && strings.EqualFold(base.Hostname(), host) {
+		return len(r.redirectHosts) == 0 || matchHost(r.redirectHosts, host)
+	}
+
+	return matchHost(r.redirectHosts, host)
+}
+
+// matchHost reports whether host is one of hosts, "*.example.com" matches any subdomain of example.com.
+func matchHost(hosts []string, host string) bool {
+	host = strings.ToLower(host)
+	for _, allow := range hosts {
+		allow = strings.ToLower(allow)
+		if allow == host || (strings.HasPrefix(allow, "*.") && strings.HasSuffix(host, allow[1:])) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func firstValue(header string) string {
+	value, _, _ := strings.Cut(header, ",")
+	return strings.TrimSpace(value)
+}
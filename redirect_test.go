@@ -0,0 +1,59 @@
+package gola
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponse_Redirect(t *testing.T) {
+	response := NewResponse()
+	assert.NotNil(t, response.Redirect("/login", 200))
+	assert.NotNil(t, response.Redirect("/login", 304))
+	assert.Nil(t, response.Redirect("/login", 302))
+	assert.Equal(t, 302, response.StatusCode())
+	assert.Equal(t, "/login", response.GetHeader("Location"))
+	assert.Equal(t, ErrRedirectNotAllowed, response.Redirect("https://evil.com/", 302))
+	assert.Equal(t, ErrRedirectNotAllowed, response.Redirect("//evil.com/", 302))
+	assert.Equal(t, ErrRedirectNotAllowed, response.Redirect("/\\evil.com/", 302))
+	assert.Equal(t, ErrRedirectNotAllowed, response.Redirect("javascript:alert(1)", 302))
+}
+
+func TestGoLA_Redirect(t *testing.T) {
+	goLA := NewServe()
+	var location string
+	goLA.Route().Post("/user/:user_id/avatar", HandlerFunc(func(ctx context.Context, request Request, response Response) error {
+		return response.SeeOther(location)
+	}))
+
+	send := func(headers map[string][]string) events.ALBTargetGroupResponse {
+		response, err := goLA.Register(context.Background(), events.ALBTargetGroupRequest{Path: "/user/1/avatar", HTTPMethod: "POST", MultiValueHeaders: headers})
+		assert.Nil(t, err)
+		return response
+	}
+
+	forwarded := map[string][]string{"Host": {"internal"}, "X-Forwarded-Host": {"api.example.com"}, "X-Forwarded-Proto": {"http"}}
+	location = "../profile?tab=avatar"
+	response := send(forwarded)
+	assert.Equal(t, 303, response.StatusCode)
+	assert.Equal(t, []string{"http://api.example.com/user/profile?tab=avatar"}, response.MultiValueHeaders["Location"])
+
+	location = "/done"
+	response = send(map[string][]string{"Host": {"api.example.com"}})
+	assert.Equal(t, []string{"https://api.example.com/done"}, response.MultiValueHeaders["Location"])
+	response = send(nil)
+	assert.Equal(t, []string{"/done"}, response.MultiValueHeaders["Location"])
+
+	location = "https://api.example.com/done"
+	assert.Equal(t, 303, send(forwarded).StatusCode)
+	location = "https://www.example.com/done"
+	assert.Equal(t, 400, send(forwarded).StatusCode)
+
+	goLA.RedirectHosts = []string{"*.example.com"}
+	assert.Equal(t, 303, send(forwarded).StatusCode)
+	location = "/done"
+	response = send(map[string][]string{"Host": {"evil.com"}})
+	assert.Equal(t, []string{"/done"}, response.MultiValueHeaders["Location"])
+}
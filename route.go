@@ -1,10 +1,16 @@
 package gola
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
 	"sort"
 	"strings"
+	"sync/atomic"
 )
 
 type NodeType int
@@ -19,48 +25,97 @@ const (
 type Node interface {
 	Parent() Node
 	Handlers() []Handler
+	MethodHandlers() map[string][]Handler
 	Name() string
 	ParameterName() string
+	ParameterConstraint() string
 	Children() map[string]Node
 	NodeType() NodeType
 }
 
 type _Node struct {
-	parent        Node
-	name          string
-	parameterName string
-	handlers      []Handler
-	children      map[string]Node
-	nodeType      NodeType
+	parent            Node
+	name              string
+	parameterName     string
+	declared          bool
+	parameterChild    bool
+	constraint        string
+	pattern           *regexp.Regexp
+	handlers          []Handler
+	methodHandlers    map[string][]Handler
+	middlewares       []Middleware
+	methodMiddlewares map[string][]Middleware
+	children          map[string]Node
+	nodeType          NodeType
+	// slashes records registered forms for TrailingSlashStrict, [0] is the node itself and [1] its parameter or wildcard
+	slashes [2]slashForm
+}
+
+type slashForm struct {
+	with, without bool
+}
+
+// registerSlash records the form path is registered in, path ending by parameter or wildcard is the form of [1].
+func (n *_Node) registerSlash(path string, slash bool) {
+	form := &n.slashes[0]
+	if last := path[strings.LastIndex(path, "/")+1:]; last == "*" || strings.Index(last, ":") == 0 {
+		form = &n.slashes[1]
+	}
+
+	if slash {
+		form.with = true
+	} else {
+		form.without = true
+	}
+}
+
+// servesSlash reports whether the form of request is registered, the other form is used when nothing is registered for it.
+func (n *_Node) servesSlash(isLast bool, slash bool) bool {
+	form, other := n.slashes[1], n.slashes[0]
+	if isLast {
+		form, other = other, form
+	}
+
+	if !form.with && !form.without {
+		form = other
+	}
+
+	return (slash && form.with) || (!slash && (form.without || !form.with))
 }
 
 func (n *_Node) path() string {
-	rtn := ""
-	var current Node = n
-	if current.NodeType() == NodeTypeRoot {
+	if n.nodeType == NodeTypeRoot {
 		return "/"
 	}
 
-	for {
-		switch current.NodeType() {
-		case NodeTypeRoot:
-			rtn = fmt.Sprintf("/%s", rtn)
-		case NodeTypeEndPoint:
-			rtn = fmt.Sprintf("%s/:%s/%s", current.Name(), current.ParameterName(), rtn)
-		case NodeTypeRecursive:
-			rtn = fmt.Sprintf("%s/%s*", current.Name(), rtn)
-		case NodeTypeNamespace:
-			rtn = fmt.Sprintf("%s/%s", current.Name(), rtn)
+	rtn := n.name
+	switch n.nodeType {
+	case NodeTypeEndPoint:
+		if n.declared {
+			rtn = fmt.Sprintf("%s/%s", rtn, n.parameter())
 		}
+	case NodeTypeRecursive:
+		rtn = fmt.Sprintf("%s/*", rtn)
+	}
 
-		if current.Parent() == nil {
-			break
+	for current := n; current.parent != nil; current = current.parent.(*_Node) {
+		parent := current.parent.(*_Node)
+		if current.parameterChild {
+			rtn = fmt.Sprintf("%s/%s/%s", parent.name, parent.parameter(), rtn)
+		} else {
+			rtn = fmt.Sprintf("%s/%s", parent.name, rtn)
 		}
+	}
+
+	return rtn
+}
 
-		current = current.Parent()
+func (n *_Node) parameter() string {
+	if n.constraint != "" {
+		return fmt.Sprintf(":%s<%s>", n.parameterName, n.constraint)
 	}
 
-	return strings.TrimRight(rtn, "/")
+	return fmt.Sprintf(":%s", n.parameterName)
 }
 
 func (n *_Node) Parent() Node {
@@ -71,6 +126,10 @@ func (n *_Node) Handlers() []Handler {
 	return n.handlers
 }
 
+func (n *_Node) MethodHandlers() map[string][]Handler {
+	return n.methodHandlers
+}
+
 func (n *_Node) Name() string {
 	return n.name
 }
@@ -79,6 +138,14 @@ func (n *_Node) ParameterName() string {
 	return n.parameterName
 }
 
+func (n *_Node) ParameterConstraint() string {
+	return n.constraint
+}
+
+func (n *_Node) matchParameter(value string) bool {
+	return n.pattern == nil || n.pattern.MatchString(value)
+}
+
 func (n *_Node) Children() map[string]Node {
 	return n.children
 }
@@ -87,17 +154,63 @@ func (n *_Node) NodeType() NodeType {
 	return n.nodeType
 }
 
+func nodeHandlers(node Node, method string) (handlers []Handler, middlewares []Middleware, allow []string) {
+	n := node.(*_Node)
+	if handlers, f := n.methodHandlers[method]; f {
+		return handlers, n.methodMiddlewares[method], nil
+	}
+
+	if handlers, f := n.methodHandlers[http.MethodGet]; f && method == http.MethodHead {
+		return handlers, n.methodMiddlewares[http.MethodGet], nil
+	}
+
+	if len(n.handlers) > 0 || len(n.methodHandlers) == 0 {
+		return n.handlers, n.middlewares, nil
+	}
+
+	for m := range n.methodHandlers {
+		allow = append(allow, m)
+	}
+
+	if _, f := n.methodHandlers[http.MethodHead]; !f {
+		if _, f := n.methodHandlers[http.MethodGet]; f {
+			allow = append(allow, http.MethodHead)
+		}
+	}
+
+	sort.Strings(allow)
+	return nil, nil, allow
+}
+
+var ErrRouteConflict = errors.New("route conflict")
+
+var ErrRouteURL = errors.New("route url")
+
 type Route struct {
-	root Node
+	root        Node
+	prefix      string
+	handlers    []Handler
+	middlewares []Middleware
+	name        string
+	tree        *routeTree
+}
+
+type routeTree struct {
+	conflicts []error
+	names     map[string]string
+	radix     atomic.Pointer[radixNode]
 }
 
 func NewRoute() *Route {
-	return &Route{root: &_Node{
-		parent:   nil,
-		name:     "",
-		children: map[string]Node{},
-		nodeType: NodeTypeRoot,
-	}}
+	return &Route{
+		root: &_Node{
+			parent:   nil,
+			name:     "",
+			children: map[string]Node{},
+			nodeType: NodeTypeRoot,
+		},
+		tree: &routeTree{names: map[string]string{}},
+	}
 }
 
 func (r *Route) traverse(node Node, result map[string]int) {
@@ -124,67 +237,449 @@ func (r *Route) String() string {
 	}
 
 	sort.Strings(paths)
-	marshal, _ := json.Marshal(paths)
-	return string(marshal)
+	marshal := &strings.Builder{}
+	encoder := json.NewEncoder(marshal)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(paths)
+	return strings.TrimSpace(marshal.String())
 }
 
 func (r *Route) SetRootHandlers(handlers ...Handler) *Route {
-	r.root.(*_Node).handlers = handlers
+	return r.SetEndpoint("", handlers...)
+}
+
+func (r *Route) Group(prefix string, handlers ...Handler) *Route {
+	return &Route{
+		root:        r.root,
+		prefix:      r.path(prefix),
+		handlers:    r.chain(handlers),
+		middlewares: r.middlewares[:len(r.middlewares):len(r.middlewares)],
+		tree:        r.tree,
+	}
+}
+
+// Use appends middlewares to the endpoints registered by this route and its groups afterwards.
+func (r *Route) Use(middlewares ...Middleware) *Route {
+	r.middlewares = append(r.middlewares, middlewares...)
 	return r
 }
 
+// With returns a route of the same prefix, the endpoints registered by it are wrapped by middlewares.
+func (r *Route) With(middlewares ...Middleware) *Route {
+	route := r.Group("")
+	route.middlewares = append(route.middlewares, middlewares...)
+	return route
+}
+
+// Named returns a route of the same prefix, the endpoint registered by it is named for URL.
+func (r *Route) Named(name string) *Route {
+	route := r.Group("")
+	route.name = name
+	return route
+}
+
+// RouteName names the endpoint when it is given among handlers of SetEndpoint or Handle, it is not run.
+type RouteName string
+
+func (n RouteName) Run(ctx context.Context, request Request, response Response) (er error) {
+	return nil
+}
+
+// named picks RouteName out of handlers and returns the route to register them by.
+func (r *Route) named(handlers []Handler) (*Route, []Handler) {
+	for idx, handler := range handlers {
+		if name, ok := handler.(RouteName); ok {
+			return r.Named(string(name)), append(handlers[:idx:idx], handlers[idx+1:]...)
+		}
+	}
+
+	return r, handlers
+}
+
 func (r *Route) SetEndpoint(path string, handlers ...Handler) *Route {
+	route, handlers := r.named(handlers)
+	route.setEndpoint(r.path(path), r.trailingSlash(path), handlers)
+	return r
+}
+
+func (r *Route) setEndpoint(path string, slash bool, handlers []Handler) error {
+	err := r.conflict(path, "")
+	if err != nil {
+		r.tree.conflicts = append(r.tree.conflicts, err)
+		if skipRegistration(err) {
+			return err
+		}
+	}
+
+	checkInputs(handlers)
+	node := r.endpoint(path)
+	node.handlers = r.chain(handlers)
+	node.middlewares = r.middlewares
+	node.registerSlash(path, slash)
+	r.nameEndpoint(path, slash)
+	return err
+}
+
+// nameEndpoint keeps the first path of name, another path of it is reported by conflict.
+func (r *Route) nameEndpoint(path string, slash bool) {
+	if _, f := r.tree.names[r.name]; r.name == "" || f {
+		return
+	}
+
+	if slash {
+		path += "/"
+	}
+
+	r.tree.names[r.name] = path
+}
+
+func (r *Route) Handle(method string, path string, handlers ...Handler) *Route {
+	route, handlers := r.named(handlers)
+	route.handle(strings.ToUpper(method), r.path(path), r.trailingSlash(path), handlers)
+	return r
+}
+
+func (r *Route) handle(method string, path string, slash bool, handlers []Handler) error {
+	err := r.conflict(path, method)
+	if err != nil {
+		r.tree.conflicts = append(r.tree.conflicts, err)
+		if skipRegistration(err) {
+			return err
+		}
+	}
+
+	checkInputs(handlers)
+	node := r.endpoint(path)
+	if node.methodHandlers == nil {
+		node.methodHandlers = map[string][]Handler{}
+	}
+
+	if node.methodMiddlewares == nil {
+		node.methodMiddlewares = map[string][]Middleware{}
+	}
+
+	node.methodHandlers[method] = r.chain(handlers)
+	node.methodMiddlewares[method] = r.middlewares
+	node.registerSlash(path, slash)
+	r.nameEndpoint(path, slash)
+	return err
+}
+
+func (r *Route) Get(path string, handlers ...Handler) *Route {
+	return r.Handle(http.MethodGet, path, handlers...)
+}
+
+func (r *Route) Head(path string, handlers ...Handler) *Route {
+	return r.Handle(http.MethodHead, path, handlers...)
+}
+
+func (r *Route) Post(path string, handlers ...Handler) *Route {
+	return r.Handle(http.MethodPost, path, handlers...)
+}
+
+func (r *Route) Put(path string, handlers ...Handler) *Route {
+	return r.Handle(http.MethodPut, path, handlers...)
+}
+
+func (r *Route) Delete(path string, handlers ...Handler) *Route {
+	return r.Handle(http.MethodDelete, path, handlers...)
+}
+
+func (r *Route) Patch(path string, handlers ...Handler) *Route {
+	return r.Handle(http.MethodPatch, path, handlers...)
+}
+
+func (r *Route) Options(path string, handlers ...Handler) *Route {
+	return r.Handle(http.MethodOptions, path, handlers...)
+}
+
+var parameterTypes = map[string]string{
+	"int":   `-?[0-9]+`,
+	"uint":  `[0-9]+`,
+	"alpha": `[A-Za-z]+`,
+	"uuid":  `[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`,
+}
+
+func parseParameter(parameter string) (name string, constraint string, pattern *regexp.Regexp, err error) {
+	idx := strings.Index(parameter, "<")
+	if idx < 0 || !strings.HasSuffix(parameter, ">") {
+		return parameter, "", nil, nil
+	}
+
+	name, constraint = parameter[:idx], parameter[idx+1:len(parameter)-1]
+	expr := constraint
+	if v, f := parameterTypes[constraint]; f {
+		expr = v
+	}
+
+	pattern, err = regexp.Compile(fmt.Sprintf("^(?:%s)$", expr))
+	return name, constraint, pattern, err
+}
+
+func (r *Route) path(path string) string {
+	path = strings.TrimLeft(strings.TrimRight(path, "/"), "/")
+	if r.prefix == "" {
+		return path
+	}
+
+	if path == "" {
+		return r.prefix
+	}
+
+	return r.prefix + "/" + path
+}
+
+// trailingSlash reports whether path is registered with trailing slash, root path never is.
+func (r *Route) trailingSlash(path string) bool {
+	return strings.HasSuffix(path, "/") && r.path(path) != ""
+}
+
+func (r *Route) chain(handlers []Handler) []Handler {
+	if len(r.handlers) == 0 {
+		return handlers
+	}
+
+	return append(append([]Handler{}, r.handlers...), handlers...)
+}
+
+func (r *Route) endpoint(path string) *_Node {
 	path = strings.TrimLeft(strings.TrimRight(path, "/"), "/")
 	if path == "" {
-		r.root.(*_Node).handlers = handlers
-		return r
+		return r.root.(*_Node)
 	}
 
-	current := r.root
+	r.tree.radix.Store(nil)
+	current := r.root.(*_Node)
 	parts := strings.Split(path, "/")
 	partsLen := len(parts)
+	parameterChild := false
 	for idx, part := range parts {
 		if strings.Index(part, ":") == 0 {
-			current.(*_Node).nodeType = NodeTypeEndPoint
-			current.(*_Node).parameterName = part[1:]
-			if idx+1 == partsLen {
-				current.(*_Node).handlers = handlers
+			current.nodeType = NodeTypeEndPoint
+			name, constraint, pattern, err := parseParameter(part[1:])
+			if err != nil {
+				panic(err)
 			}
 
+			current.parameterName, current.constraint, current.pattern = name, constraint, pattern
+			current.declared = true
+			parameterChild = true
 			continue
 		}
 
 		if part == "*" {
-			current.(*_Node).nodeType = NodeTypeRecursive
-			current.(*_Node).parameterName = current.Name()
-			current.(*_Node).handlers = handlers
-			return r
+			current.nodeType = NodeTypeRecursive
+			current.parameterName = current.Name()
+			return current
 		}
 
 		if v, f := current.Children()[part]; f {
-			current = v
+			current = v.(*_Node)
+			if idx+1 == partsLen && current.nodeType == NodeTypeNamespace {
+				current.nodeType = NodeTypeEndPoint
+				current.parameterName = part
+			}
 		} else {
 			node := &_Node{
-				parent:        current,
-				name:          part,
-				parameterName: "",
-				handlers:      []Handler{},
-				children:      map[string]Node{},
-				nodeType:      NodeTypeNamespace,
+				parent:         current,
+				name:           part,
+				parameterName:  "",
+				parameterChild: parameterChild,
+				handlers:       []Handler{},
+				children:       map[string]Node{},
+				nodeType:       NodeTypeNamespace,
 			}
 
 			if idx+1 == partsLen {
 				node.nodeType = NodeTypeEndPoint
 				node.parameterName = part
-				node.handlers = handlers
 			}
 
 			current.Children()[part] = node
 			current = node
 		}
+
+		parameterChild = false
 	}
 
-	return r
+	return current
+}
+
+func (r *Route) Validate() []error {
+	return append([]error{}, r.tree.conflicts...)
+}
+
+// routeConflict marks the conflict whose path is not put into tree, registering it would rename
+// or retype the node shared with the routes registered before.
+type routeConflict struct {
+	err error
+}
+
+func (c *routeConflict) Error() string {
+	return c.err.Error()
+}
+
+func (c *routeConflict) Unwrap() error {
+	return c.err
+}
+
+func skipRegistration(err error) bool {
+	var c *routeConflict
+	return errors.As(err, &c)
+}
+
+func (r *Route) conflict(path string, method string) error {
+	path = strings.TrimLeft(strings.TrimRight(path, "/"), "/")
+	if err := r.pathConflict(path, method); err != nil {
+		return err
+	}
+
+	if registered, f := r.tree.names[r.name]; r.name != "" && f && strings.TrimSuffix(registered, "/") != path {
+		return fmt.Errorf("%w: name %s of /%s is used by /%s", ErrRouteConflict, r.name, path, registered)
+	}
+
+	return nil
+}
+
+func (r *Route) pathConflict(path string, method string) error {
+	current := r.root.(*_Node)
+	var parts []string
+	if path != "" {
+		parts = strings.Split(path, "/")
+	}
+
+	if err := parameterError(path); err != nil {
+		return err
+	}
+
+	for idx, part := range parts {
+		if part == "*" {
+			if current.nodeType != NodeTypeRecursive && len(current.children) > 0 {
+				return &routeConflict{fmt.Errorf("%w: wildcard /%s shadows children of %s", ErrRouteConflict, path, current.path())}
+			}
+
+			if current.nodeType != NodeTypeRecursive && current.declared {
+				return &routeConflict{fmt.Errorf("%w: wildcard /%s shadows parameter :%s", ErrRouteConflict, path, current.parameterName)}
+			}
+
+			break
+		}
+
+		if current.nodeType == NodeTypeRecursive {
+			return fmt.Errorf("%w: /%s is shadowed by wildcard %s", ErrRouteConflict, path, current.path())
+		}
+
+		if strings.Index(part, ":") == 0 {
+			name, constraint, _, _ := parseParameter(part[1:])
+			if current.declared && (name != current.parameterName || constraint != current.constraint) {
+				return &routeConflict{fmt.Errorf("%w: /%s declares :%s, %s already declares :%s", ErrRouteConflict, path, part[1:], current.path(), current.parameter())}
+			}
+
+			continue
+		}
+
+		child, f := current.children[part]
+		if !f {
+			return nil
+		}
+
+		if parameterChild := child.(*_Node).parameterChild; parameterChild != (idx > 0 && strings.Index(parts[idx-1], ":") == 0) {
+			return fmt.Errorf("%w: /%s is ambiguous with %s", ErrRouteConflict, path, child.(*_Node).path())
+		}
+
+		current = child.(*_Node)
+	}
+
+	if method == "" && len(current.handlers) > 0 {
+		return fmt.Errorf("%w: /%s overwrites handlers", ErrRouteConflict, path)
+	}
+
+	if _, f := current.methodHandlers[method]; method != "" && f {
+		return fmt.Errorf("%w: %s /%s overwrites handlers", ErrRouteConflict, method, path)
+	}
+
+	return nil
+}
+
+// parameterError reports the path parameter whose constraint can not be compiled.
+func parameterError(path string) error {
+	for _, part := range strings.Split(path, "/") {
+		if strings.Index(part, ":") == 0 {
+			if _, _, _, err := parseParameter(part[1:]); err != nil {
+				return &routeConflict{fmt.Errorf("%w: /%s %s", ErrRouteConflict, path, err.Error())}
+			}
+		}
+	}
+
+	return nil
+}
+
+type StrictRoute struct {
+	route *Route
+}
+
+func (r *Route) Strict() *StrictRoute {
+	return &StrictRoute{route: r}
+}
+
+func (s *StrictRoute) Route() *Route {
+	return s.route
+}
+
+func (s *StrictRoute) Group(prefix string, handlers ...Handler) *StrictRoute {
+	return s.route.Group(prefix, handlers...).Strict()
+}
+
+func (s *StrictRoute) Named(name string) *StrictRoute {
+	return s.route.Named(name).Strict()
+}
+
+func (s *StrictRoute) SetEndpoint(path string, handlers ...Handler) error {
+	route, handlers := s.route.named(handlers)
+	slash, path := route.trailingSlash(path), route.path(path)
+	if err := route.conflict(path, ""); err != nil {
+		return err
+	}
+
+	return route.setEndpoint(path, slash, handlers)
+}
+
+func (s *StrictRoute) Handle(method string, path string, handlers ...Handler) error {
+	route, handlers := s.route.named(handlers)
+	slash, method, path := route.trailingSlash(path), strings.ToUpper(method), route.path(path)
+	if err := route.conflict(path, method); err != nil {
+		return err
+	}
+
+	return route.handle(method, path, slash, handlers)
+}
+
+func (s *StrictRoute) Get(path string, handlers ...Handler) error {
+	return s.Handle(http.MethodGet, path, handlers...)
+}
+
+func (s *StrictRoute) Head(path string, handlers ...Handler) error {
+	return s.Handle(http.MethodHead, path, handlers...)
+}
+
+func (s *StrictRoute) Post(path string, handlers ...Handler) error {
+	return s.Handle(http.MethodPost, path, handlers...)
+}
+
+func (s *StrictRoute) Put(path string, handlers ...Handler) error {
+	return s.Handle(http.MethodPut, path, handlers...)
+}
+
+func (s *StrictRoute) Delete(path string, handlers ...Handler) error {
+	return s.Handle(http.MethodDelete, path, handlers...)
+}
+
+func (s *StrictRoute) Patch(path string, handlers ...Handler) error {
+	return s.Handle(http.MethodPatch, path, handlers...)
+}
+
+func (s *StrictRoute) Options(path string, handlers ...Handler) error {
+	return s.Handle(http.MethodOptions, path, handlers...)
 }
 
 func (r *Route) FindNode(path string) Node {
@@ -193,61 +688,81 @@ func (r *Route) FindNode(path string) Node {
 }
 
 func (r *Route) RouteNode(path string) (node Node, parameters map[string]string, isLast bool) {
-	path = strings.TrimLeft(strings.TrimRight(path, "/"), "/")
-	params := map[string]string{}
-	if path == "" {
-		return r.root, nil, true
+	if matched, parameters, isLast := r.routeNode(path, false); matched != nil {
+		return matched, parameters, isLast
 	}
 
-	parts := strings.Split(path, "/")
-	nodeLens := len(parts)
-	current := r.root
-	next := r.root
+	return nil, nil, false
+}
+
+func (r *Route) routeNode(path string, fold bool) (node *_Node, parameters map[string]string, isLast bool) {
+	radix := r.tree.radix.Load()
+	if radix == nil {
+		radix = compileRadix(r.root.(*_Node))
+		r.tree.radix.Store(radix)
+	}
+
+	matched, parameters, isLast := radix.match(strings.TrimLeft(strings.TrimRight(path, "/"), "/"), nil, fold)
+	if matched == nil {
+		return nil, nil, false
+	}
+
+	return matched.node, parameters, isLast
+}
+
+// URL builds the path of endpoint registered by Named, every path parameter must be given in params
+// and satisfy its constraint, the wildcard parameter may be empty. Values are escaped, query is appended when not empty.
+func (r *Route) URL(name string, params map[string]string, query url.Values) (string, error) {
+	path, f := r.tree.names[name]
+	if !f {
+		return "", fmt.Errorf("%w: route %s is not named", ErrRouteURL, name)
+	}
+
+	var parts []string
+	if path != "" {
+		parts = strings.Split(path, "/")
+	}
+
+	var segments []string
 	for idx, part := range parts {
-		next = current.Children()[part]
-		switch current.NodeType() {
-		case NodeTypeRoot, NodeTypeEndPoint:
-			if idx+1 == nodeLens {
-				if next == nil {
-					if current == r.root && part != "" {
-						return nil, nil, false
-					} else {
-						params[current.ParameterName()] = part
-						return current, params, false
-					}
-				} else {
-					return next, params, true
-				}
-			} else {
-				if next == nil {
-					if _, f := current.Children()[parts[idx+1]]; f {
-						params[current.ParameterName()] = part
-						continue
-					} else {
-						return nil, nil, false
-					}
-				} else {
-					current = next
+		switch {
+		case part == "*":
+			wildcard := ""
+			if idx > 0 {
+				wildcard = parts[idx-1]
+			}
+
+			value, f := params[wildcard]
+			if !f {
+				return "", fmt.Errorf("%w: route %s requires parameter %s", ErrRouteURL, name, wildcard)
+			}
+
+			for _, v := range strings.Split(strings.Trim(value, "/"), "/") {
+				if v != "" {
+					segments = append(segments, url.PathEscape(v))
 				}
 			}
-		case NodeTypeRecursive:
-			if next == nil {
-				params[current.ParameterName()] = part
+		case strings.Index(part, ":") == 0:
+			parameter, _, pattern, _ := parseParameter(part[1:])
+			value, f := params[parameter]
+			if !f || value == "" {
+				return "", fmt.Errorf("%w: route %s requires parameter %s", ErrRouteURL, name, parameter)
 			}
 
-			return current, params, false
-		case NodeTypeNamespace:
-			if next == nil {
-				return nil, nil, false
+			if pattern != nil && !pattern.MatchString(value) {
+				return "", fmt.Errorf("%w: parameter %s of route %s does not match %s", ErrRouteURL, parameter, name, pattern.String())
 			}
 
-			current = next
+			segments = append(segments, url.PathEscape(value))
+		default:
+			segments = append(segments, part)
 		}
 	}
 
-	if current.NodeType() == NodeTypeNamespace {
-		return nil, nil, false
+	target := "/" + strings.Join(segments, "/")
+	if len(query) > 0 {
+		target += "?" + query.Encode()
 	}
 
-	return current, params, current == next
+	return target, nil
 }
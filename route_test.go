@@ -2,6 +2,7 @@ package gola
 
 import (
 	"context"
+	"net/url"
 	"testing"
 
 	"github.com/aws/aws-lambda-go/events"
@@ -130,3 +131,262 @@ func TestRoute_SetEndpoint(t *testing.T) {
 	assert.Equal(t, 500, response.StatusCode)
 	assert.Nil(t, err)
 }
+
+type DefaultMethodHandler struct {
+	method string
+}
+
+func (d *DefaultMethodHandler) Run(ctx context.Context, request Request, response Response) (er error) {
+	response.SetHeader("METHOD", d.method)
+	return nil
+}
+
+func TestRoute_Handle(t *testing.T) {
+	goLA := NewServe()
+	route := goLA.Route()
+	route.
+		Get("/user/:user_id", &DefaultMethodHandler{method: "GET"}).
+		Post("/user/:user_id", &DefaultMethodHandler{method: "POST"}).
+		Delete("/user/:user_id", &DefaultMethodHandler{method: "DELETE"}).
+		SetEndpoint("/any", &DefaultMethodHandler{method: "ANY"}).
+		Put("/any", &DefaultMethodHandler{method: "PUT"})
+
+	node := route.FindNode("/user/123")
+	assert.NotNil(t, node)
+	assert.Equal(t, 3, len(node.MethodHandlers()))
+
+	response, err := goLA.Register(context.Background(), events.ALBTargetGroupRequest{Path: "/user/123", HTTPMethod: "GET"})
+	assert.Nil(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, []string{"GET"}, response.MultiValueHeaders["Method"])
+
+	response, err = goLA.Register(context.Background(), events.ALBTargetGroupRequest{Path: "/user", HTTPMethod: "POST"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"POST"}, response.MultiValueHeaders["Method"])
+
+	response, err = goLA.Register(context.Background(), events.ALBTargetGroupRequest{Path: "/user/123", HTTPMethod: "HEAD"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"GET"}, response.MultiValueHeaders["Method"])
+
+	response, err = goLA.Register(context.Background(), events.ALBTargetGroupRequest{Path: "/user/123", HTTPMethod: "PATCH"})
+	assert.Nil(t, err)
+	assert.Equal(t, 405, response.StatusCode)
+	assert.Equal(t, []string{"DELETE, GET, HEAD, POST"}, response.MultiValueHeaders["Allow"])
+
+	response, err = goLA.Register(context.Background(), events.ALBTargetGroupRequest{Path: "/any", HTTPMethod: "PUT"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"PUT"}, response.MultiValueHeaders["Method"])
+
+	response, err = goLA.Register(context.Background(), events.ALBTargetGroupRequest{Path: "/any", HTTPMethod: "PATCH"})
+	assert.Nil(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, []string{"ANY"}, response.MultiValueHeaders["Method"])
+
+	response, err = goLA.Register(context.Background(), events.ALBTargetGroupRequest{Path: "/none", HTTPMethod: "GET"})
+	assert.Nil(t, err)
+	assert.Equal(t, 404, response.StatusCode)
+}
+
+type DefaultHeaderHandler struct {
+	name, value string
+}
+
+func (d *DefaultHeaderHandler) Run(ctx context.Context, request Request, response Response) (er error) {
+	response.AddHeader(d.name, d.value)
+	return nil
+}
+
+func TestRoute_Group(t *testing.T) {
+	goLA := NewServe()
+	route := goLA.Route()
+	auth := route.Group("/auth", &DefaultHeaderHandler{name: "CHAIN", value: "auth"})
+	user := auth.Group("/group/user/", &DefaultHeaderHandler{name: "CHAIN", value: "user"})
+	user.
+		SetEndpoint("/:user_id", &DefaultHeaderHandler{name: "CHAIN", value: "endpoint"}).
+		Get("/:user_id/book/:book", &DefaultHeaderHandler{name: "CHAIN", value: "book"})
+	auth.SetEndpoint("/", &DefaultHeaderHandler{name: "CHAIN", value: "root"})
+
+	node := route.FindNode("/auth/group/user/123")
+	assert.NotNil(t, node)
+	assert.Equal(t, 3, len(node.Handlers()))
+	assert.Equal(t, 3, len(route.FindNode("/auth/group/user/123/book/book1").MethodHandlers()["GET"]))
+	assert.Equal(t, 2, len(route.FindNode("/auth").Handlers()))
+
+	response, err := goLA.Register(context.Background(), events.ALBTargetGroupRequest{Path: "/auth/group/user/123", HTTPMethod: "GET"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"auth", "user", "endpoint"}, response.MultiValueHeaders["Chain"])
+
+	response, err = goLA.Register(context.Background(), events.ALBTargetGroupRequest{Path: "/auth/group/user/123/book/book1", HTTPMethod: "GET"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"auth", "user", "book"}, response.MultiValueHeaders["Chain"])
+}
+
+type DefaultTypedHandler struct {
+	t *testing.T
+}
+
+func (d *DefaultTypedHandler) Run(ctx context.Context, request Request, response Response) (er error) {
+	if request.PathParameter("user_id") != "" {
+		userId, err := request.PathParameterInt("user_id")
+		assert.Nil(d.t, err)
+		assert.Equal(d.t, 123, userId)
+	}
+
+	if request.PathParameter("id") != "" {
+		id, err := request.PathParameterUUID("id")
+		assert.Nil(d.t, err)
+		assert.Equal(d.t, "0e0c3b8a-9d1f-4a8e-b5d2-3c1f2e4d5a6b", id)
+		_, err = request.PathParameterInt("id")
+		assert.NotNil(d.t, err)
+	}
+
+	return nil
+}
+
+func TestRoute_TypedParameter(t *testing.T) {
+	goLA := NewServe()
+	route := goLA.Route()
+	route.
+		SetEndpoint("/user/:user_id<int>", &DefaultTypedHandler{t: t}).
+		SetEndpoint("/user/me", &TestDefaultEmptyHandler{}).
+		SetEndpoint("/article/:slug<[a-z-]+>/comment/:comment_id<uint>", &TestDefaultEmptyHandler{}).
+		SetEndpoint("/item/:id<uuid>", &DefaultTypedHandler{t: t})
+
+	node, parameters, _ := route.RouteNode("/user/123")
+	assert.NotNil(t, node)
+	assert.Equal(t, "123", parameters["user_id"])
+	assert.Equal(t, "int", node.ParameterConstraint())
+	assert.Nil(t, route.FindNode("/user/abc"))
+	assert.Equal(t, "me", route.FindNode("/user/me").Name())
+
+	node, parameters, _ = route.RouteNode("/article/hello-world/comment/7")
+	assert.NotNil(t, node)
+	assert.Equal(t, "hello-world", parameters["slug"])
+	assert.Equal(t, "7", parameters["comment_id"])
+	assert.Nil(t, route.FindNode("/article/Hello/comment/7"))
+	assert.Nil(t, route.FindNode("/article/hello/comment/-7"))
+
+	assert.NotNil(t, route.FindNode("/item/0E0C3B8A-9D1F-4A8E-B5D2-3C1F2E4D5A6B"))
+	assert.Nil(t, route.FindNode("/item/123"))
+	assert.Contains(t, route.String(), "/item/:id<uuid>")
+
+	response, err := goLA.Register(context.Background(), events.ALBTargetGroupRequest{Path: "/user/123", HTTPMethod: "GET"})
+	assert.Nil(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+
+	response, err = goLA.Register(context.Background(), events.ALBTargetGroupRequest{Path: "/user/abc", HTTPMethod: "GET"})
+	assert.Nil(t, err)
+	assert.Equal(t, 404, response.StatusCode)
+
+	response, err = goLA.Register(context.Background(), events.ALBTargetGroupRequest{Path: "/item/0E0C3B8A-9D1F-4A8E-B5D2-3C1F2E4D5A6B", HTTPMethod: "GET"})
+	assert.Nil(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+}
+
+func TestRoute_Validate(t *testing.T) {
+	route := NewRoute()
+	route.
+		SetEndpoint("/user/:user_id", &TestDefaultEmptyHandler{}).
+		SetEndpoint("/user/:user_id/book/:book", &TestDefaultEmptyHandler{}).
+		Get("/user/:user_id", &TestDefaultEmptyHandler{}).
+		SetEndpoint("/wild/*", &DefaultWildHandler{})
+	assert.Empty(t, route.Validate())
+
+	route.
+		SetEndpoint("/user/:id/profile", &TestDefaultEmptyHandler{}).
+		SetEndpoint("/user/:user_id", &TestDefaultEmptyHandler{}).
+		Get("/user/:user_id", &TestDefaultEmptyHandler{}).
+		SetEndpoint("/wild/card", &TestDefaultEmptyHandler{}).
+		SetEndpoint("/user/*", &DefaultWildHandler{}).
+		SetEndpoint("/item/:id<[a-z>", &TestDefaultEmptyHandler{}).
+		Get("/item/:id<(>/detail", &TestDefaultEmptyHandler{})
+	conflicts := route.Validate()
+	assert.Equal(t, 7, len(conflicts))
+	assert.Nil(t, route.FindNode("/item/a"))
+	_, parameters, _ := route.RouteNode("/user/abc")
+	assert.Equal(t, "abc", parameters["user_id"])
+
+	route = NewRoute()
+	route.
+		SetEndpoint("/a/:x", &TestDefaultEmptyHandler{}).
+		SetEndpoint("/a/:y<int>/c", &TestDefaultEmptyHandler{}).
+		Get("/a/:x<int>", &TestDefaultEmptyHandler{}).
+		SetEndpoint("/a/*", &DefaultWildHandler{})
+	assert.Equal(t, 3, len(route.Validate()))
+	node, parameters, _ := route.RouteNode("/a/hello")
+	assert.NotNil(t, node)
+	assert.Equal(t, "hello", parameters["x"])
+	assert.Equal(t, "", node.ParameterConstraint())
+	assert.Nil(t, route.FindNode("/a/1/c"))
+	for _, err := range conflicts {
+		assert.ErrorIs(t, err, ErrRouteConflict)
+	}
+
+	strict := NewRoute().Strict()
+	assert.Nil(t, strict.SetEndpoint("/user/:user_id<int>", &TestDefaultEmptyHandler{}))
+	assert.Nil(t, strict.Handle("GET", "/user/:user_id<int>", &TestDefaultEmptyHandler{}))
+	assert.ErrorIs(t, strict.SetEndpoint("/user/:user_id", &TestDefaultEmptyHandler{}), ErrRouteConflict)
+	assert.ErrorIs(t, strict.Handle("get", "/user/:user_id<int>", &TestDefaultEmptyHandler{}), ErrRouteConflict)
+	assert.ErrorIs(t, strict.Group("/user").SetEndpoint("/*", &DefaultWildHandler{}), ErrRouteConflict)
+	assert.ErrorIs(t, strict.SetEndpoint("/item/:id<[a-z>", &TestDefaultEmptyHandler{}), ErrRouteConflict)
+	assert.Nil(t, strict.Get("/book/:book", &TestDefaultEmptyHandler{}))
+	assert.Nil(t, strict.Post("/book/:book", &TestDefaultEmptyHandler{}))
+	assert.ErrorIs(t, strict.Get("/book/:book", &TestDefaultEmptyHandler{}), ErrRouteConflict)
+	assert.Nil(t, strict.Group("/book").Delete("/:book", &TestDefaultEmptyHandler{}))
+	assert.Len(t, strict.Route().FindNode("/book/1").MethodHandlers(), 3)
+	assert.Equal(t, "int", strict.Route().FindNode("/user/123").ParameterConstraint())
+	assert.Empty(t, strict.Route().Validate())
+}
+
+func TestRoute_URL(t *testing.T) {
+	route := NewRoute()
+	route.Named("user").Get("/user/:user_id<int>", &TestDefaultEmptyHandler{})
+	route.Group("/auth").Named("book").SetEndpoint("/user/:user_id/book/:book", &TestDefaultEmptyHandler{})
+	route.Named("files").SetEndpoint("/files/*", &TestDefaultEmptyHandler{})
+	route.Named("root").SetRootHandlers(&TestDefaultEmptyHandler{})
+
+	link, err := route.URL("user", map[string]string{"user_id": "123"}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "/user/123", link)
+	_, err = route.URL("user", map[string]string{"user_id": "abc"}, nil)
+	assert.ErrorIs(t, err, ErrRouteURL)
+	_, err = route.URL("user", nil, nil)
+	assert.ErrorIs(t, err, ErrRouteURL)
+	_, err = route.URL("none", nil, nil)
+	assert.ErrorIs(t, err, ErrRouteURL)
+
+	link, err = route.URL("book", map[string]string{"user_id": "john doe", "book": "a/b"}, url.Values{"page": {"2"}})
+	assert.Nil(t, err)
+	assert.Equal(t, "/auth/user/john%20doe/book/a%2Fb?page=2", link)
+	_, parameters, _ := route.RouteNode("/auth/user/john%20doe/book/a%2Fb")
+	assert.Equal(t, "john doe", parameters["user_id"])
+	assert.Equal(t, "a/b", parameters["book"])
+
+	link, _ = route.URL("files", map[string]string{"files": "a b/c.txt"}, nil)
+	assert.Equal(t, "/files/a%20b/c.txt", link)
+	link, _ = route.URL("files", map[string]string{"files": ""}, nil)
+	assert.Equal(t, "/files", link)
+	link, _ = route.URL("root", nil, nil)
+	assert.Equal(t, "/", link)
+	route.Named("list").Get("/book/:book/", &TestDefaultEmptyHandler{})
+	link, _ = route.URL("list", map[string]string{"book": "1"}, nil)
+	assert.Equal(t, "/book/1/", link)
+
+	route.Named("user").Post("/user/:user_id<int>", &TestDefaultEmptyHandler{})
+	assert.Empty(t, route.Validate())
+	assert.ErrorIs(t, route.Strict().Named("user").SetEndpoint("/member/:id", &TestDefaultEmptyHandler{}), ErrRouteConflict)
+	route.Named("user").SetEndpoint("/member/:id", &TestDefaultEmptyHandler{})
+	assert.Len(t, route.Validate(), 1)
+	link, _ = route.URL("user", map[string]string{"user_id": "1"}, nil)
+	assert.Equal(t, "/user/1", link)
+
+	route.Group("/v2").SetEndpoint("/item/:item_id", RouteName("item"), &TestDefaultEmptyHandler{})
+	assert.Len(t, route.FindNode("/v2/item/1").Handlers(), 1)
+	link, _ = route.URL("item", map[string]string{"item_id": "a b"}, nil)
+	assert.Equal(t, "/v2/item/a%20b", link)
+	assert.Nil(t, route.Strict().Get("/tag/:tag", RouteName("tag"), &TestDefaultEmptyHandler{}))
+	assert.Len(t, route.FindNode("/tag/1").MethodHandlers()["GET"], 1)
+	assert.ErrorIs(t, route.Strict().SetEndpoint("/label/:tag", RouteName("tag")), ErrRouteConflict)
+	link, _ = route.URL("tag", map[string]string{"tag": "go"}, nil)
+	assert.Equal(t, "/tag/go", link)
+}
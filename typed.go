@@ -0,0 +1,167 @@
+package gola
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	httpheadername "github.com/kklab-com/gone-httpheadername"
+	httpstatus "github.com/kklab-com/gone-httpstatus"
+	buf "github.com/kklab-com/goth-bytebuf"
+	erresponse "github.com/kklab-com/goth-erresponse"
+	"github.com/kklab-com/goth-erresponse/constant"
+	kkerror "github.com/kklab-com/goth-kkerror"
+)
+
+var NotAcceptable erresponse.ErrorResponse = &erresponse.DefaultErrorResponse{
+	StatusCode:  httpstatus.NotAcceptable,
+	Name:        constant.ErrorInvalidRequest,
+	Description: "not acceptable",
+	DefaultKKError: kkerror.DefaultKKError{
+		ErrorLevel:    kkerror.Normal,
+		ErrorCategory: kkerror.Client,
+		ErrorCode:     "406001",
+	},
+}
+
+// StatusCoder is implemented by Typed output to choose status code other than 200.
+type StatusCoder interface {
+	StatusCode() int
+}
+
+// Typed adapts fn into Handler, In is filled by Request.Bind when it is a struct or struct pointer,
+// otherwise by Request.BindJSON. Out is encoded by Codec as JSON, or as text when Accept prefers
+// text/plain and Out is string, []byte or fmt.Stringer. nil Out is answered by 204.
+// Typed panics when `validate` tags of In are invalid.
+func Typed[In, Out any](fn func(ctx context.Context, in In) (Out, error)) Handler {
+	if err := checkTags(reflect.TypeOf((*In)(nil)).Elem()); err != nil {
+		panic(err)
+	}
+
+	return HandlerFunc(func(ctx context.Context, request Request, response Response) error {
+		in, err := bindInput[In](request)
+		if err != nil {
+			return err
+		}
+
+		out, err := fn(ctx, in)
+		if err != nil {
+			return err
+		}
+
+		return writeOutput(request, response, out)
+	})
+}
+
+func bindInput[In any](request Request) (In, error) {
+	var in In
+	t := reflect.TypeOf(&in).Elem()
+	switch {
+	case t.Kind() == reflect.Struct:
+		if t.NumField() == 0 {
+			return in, nil
+		}
+
+		return in, request.Bind(&in)
+	case t.Kind() == reflect.Pointer && t.Elem().Kind() == reflect.Struct:
+		v := reflect.New(t.Elem())
+		reflect.ValueOf(&in).Elem().Set(v)
+		return in, request.Bind(v.Interface())
+	case len(request.Body().Bytes()) > 0:
+		return in, request.BindJSON(&in)
+	}
+
+	return in, nil
+}
+
+func writeOutput(request Request, response Response, out any) error {
+	if v := reflect.ValueOf(out); !v.IsValid() || (isNillable(v.Kind()) && v.IsNil()) {
+		response.SetStatusCode(httpstatus.NoContent)
+		return nil
+	}
+
+	code := httpstatus.OK
+	if v, ok := out.(StatusCoder); ok {
+		code = v.StatusCode()
+	}
+
+	text, isText := textOutput(out)
+	switch negotiate(request.GetHeader(httpheadername.Accept), isText) {
+	case "application/json":
+		return response.JSON(code, out)
+	case "text/plain":
+		response.SetStatusCode(code).SetContentType("text/plain; charset=utf-8").SetBody(buf.NewByteBufString(text))
+		return nil
+	}
+
+	return NotAcceptable
+}
+
+func isNillable(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface, reflect.Func, reflect.Chan:
+		return true
+	}
+
+	return false
+}
+
+func textOutput(out any) (string, bool) {
+	switch v := out.(type) {
+	case string:
+		return v, true
+	case []byte:
+		return string(v), true
+	case fmt.Stringer:
+		return v.String(), true
+	}
+
+	return "", false
+}
+
+// negotiate returns the media type in Accept of the highest quality served by Typed, empty when none is acceptable.
+func negotiate(accept string, text bool) string {
+	if strings.TrimSpace(accept) == "" {
+		return "application/json"
+	}
+
+	type candidate struct {
+		mediaType string
+		q         float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if v, f := params["q"]; f {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+
+		if q > 0 {
+			candidates = append(candidates, candidate{mediaType: mediaType, q: q})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	for _, c := range candidates {
+		switch {
+		case c.mediaType == "application/json", c.mediaType == "application/*", c.mediaType == "*/*":
+			return "application/json"
+		case text && (c.mediaType == "text/plain" || c.mediaType == "text/*"):
+			return "text/plain"
+		}
+	}
+
+	return ""
+}
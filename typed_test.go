@@ -0,0 +1,80 @@
+package gola
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	erresponse "github.com/kklab-com/goth-erresponse"
+	"github.com/stretchr/testify/assert"
+)
+
+type GOLATestTypedIn struct {
+	UserID int    `path:"user_id" validate:"min=1"`
+	Name   string `json:"name"`
+}
+
+type GOLATestTypedOut struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func (o *GOLATestTypedOut) StatusCode() int {
+	return 201
+}
+
+func (o *GOLATestTypedOut) String() string {
+	return o.Name
+}
+
+func TestTyped(t *testing.T) {
+	goLA := NewServe()
+	goLA.Route().Put("/user/:user_id", Typed(func(ctx context.Context, in *GOLATestTypedIn) (*GOLATestTypedOut, error) {
+		if in.UserID == 404 {
+			return nil, erresponse.NotFound
+		}
+
+		if in.UserID == 204 {
+			return nil, nil
+		}
+
+		return &GOLATestTypedOut{ID: in.UserID, Name: in.Name}, nil
+	}))
+
+	goLA.Route().Get("/ping", Typed(func(ctx context.Context, in struct{}) (string, error) {
+		return "pong", nil
+	}))
+
+	send := func(method string, path string, accept string) (events.ALBTargetGroupResponse, string) {
+		response, err := goLA.Register(context.Background(), events.ALBTargetGroupRequest{Path: path, HTTPMethod: method, Body: `{"name":"kk"}`,
+			MultiValueHeaders: map[string][]string{"Content-Type": {"application/json"}, "Accept": {accept}}})
+		assert.Nil(t, err)
+		body, _ := base64.StdEncoding.DecodeString(response.Body)
+		return response, string(body)
+	}
+
+	response, body := send("PUT", "/user/1", "")
+	assert.Equal(t, 201, response.StatusCode)
+	assert.Equal(t, `{"id":1,"name":"kk"}`, body)
+
+	response, body = send("PUT", "/user/1", "text/html, text/plain;q=0.9, application/json;q=0.5")
+	assert.Equal(t, []string{"text/plain; charset=utf-8"}, response.MultiValueHeaders["Content-Type"])
+	assert.Equal(t, "kk", body)
+
+	response, _ = send("PUT", "/user/1", "image/png")
+	assert.Equal(t, 406, response.StatusCode)
+
+	response, _ = send("PUT", "/user/0", "")
+	assert.Equal(t, 422, response.StatusCode)
+
+	response, _ = send("PUT", "/user/404", "")
+	assert.Equal(t, 404, response.StatusCode)
+
+	response, _ = send("PUT", "/user/204", "")
+	assert.Equal(t, 204, response.StatusCode)
+
+	response, body = send("GET", "/ping", "text/*")
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, "pong", body)
+}